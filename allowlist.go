@@ -0,0 +1,106 @@
+package pflag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// allowsError reports whether the ParseErrorsAllowlist, or the deprecated
+// ParseErrorsWhitelist, allows the parsing error.
+func (f *FlagSet) allowsError(err error) bool {
+	switch err.(type) { //nolint:errorlint // only errors created by pflag itself are allowed
+	case *InvalidValueError:
+		return f.ParseErrorsAllowlist.InvalidValues || f.ParseErrorsWhitelist.InvalidValues
+	case *ValueRequiredError:
+		return f.ParseErrorsAllowlist.MissingValues || f.ParseErrorsWhitelist.MissingValues
+	}
+	return false
+}
+
+// failUnlessAllowed reports a parsing error allowed by the
+// ParseErrorsAllowlist as a warning, resets the flag in error to its default
+// value and returns nil. Other errors are returned by f.fail.
+func (f *FlagSet) failUnlessAllowed(err error) error {
+	if !f.allowsError(err) {
+		return f.fail(err)
+	}
+	switch e := err.(type) { //nolint:errorlint // allowed errors are never wrapped
+	case *InvalidValueError:
+		f.resetToDefault(e.flag)
+	case *ValueRequiredError:
+		f.resetToDefault(e.flag)
+	}
+	err = f.translateError(err)
+	warn := f.ParseErrorsAllowlist.Warn
+	if warn == nil {
+		warn = f.ParseErrorsWhitelist.Warn
+	}
+	if warn != nil {
+		warn(err)
+	} else {
+		_, _ = fmt.Fprintln(f.Output(), err)
+	}
+	return nil
+}
+
+// A changeTracker is a value whose first Set replaces its default, while the
+// next ones add to it, e.g. a slice.
+type changeTracker interface {
+	clearChanged()
+}
+
+// resetToDefault sets the flag back to its DefValue and forgets that it was
+// given on the command line, as if it had not been. Func flags, which have
+// no value, are only forgotten. The reset of the value is a best effort: a
+// DefValue the flag rejects is ignored.
+func (f *FlagSet) resetToDefault(flag *Flag) {
+	if flag == nil || !flag.Changed {
+		return
+	}
+	switch v := flag.Value.(type) {
+	case SliceValue:
+		values, err := readAsCSV(strings.TrimSuffix(strings.TrimPrefix(flag.DefValue, "["), "]"))
+		if err == nil {
+			_ = v.Replace(values)
+		}
+	case *stringToStringValue:
+		for k := range *v.value {
+			delete(*v.value, k)
+		}
+		setMapDefault(v, flag.DefValue)
+	case *stringToIntValue:
+		for k := range *v.value {
+			delete(*v.value, k)
+		}
+		setMapDefault(v, flag.DefValue)
+	case *stringToInt64Value:
+		for k := range *v.value {
+			delete(*v.value, k)
+		}
+		setMapDefault(v, flag.DefValue)
+	default:
+		switch flag.Value.Type() {
+		case "func", "boolfunc":
+		default:
+			_ = flag.Value.Set(flag.DefValue)
+		}
+	}
+	if v, ok := flag.Value.(changeTracker); ok {
+		v.clearChanged()
+	}
+
+	name := f.normalizeFlagName(flag.Name)
+	flag.Changed = false
+	delete(f.actual, name)
+	f.orderedActual = removeFromFlags(f.orderedActual, flag)
+	f.sortedActual = nil
+	delete(f.occurrences, name)
+}
+
+// setMapDefault adds the entries of the DefValue of a map value, e.g.
+// "[a=1,b=2]", to the emptied map.
+func setMapDefault(v Value, defValue string) {
+	if entries := strings.TrimSuffix(strings.TrimPrefix(defValue, "["), "]"); entries != "" {
+		_ = v.Set(entries)
+	}
+}
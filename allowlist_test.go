@@ -0,0 +1,134 @@
+package pflag
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestAllowInvalidValues(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	port := f.IntP("port", "p", 80, "port")
+	debug := f.Bool("debug", false, "debug")
+	var warnings []error
+	f.ParseErrorsAllowlist.InvalidValues = true
+	f.ParseErrorsAllowlist.Warn = func(err error) {
+		warnings = append(warnings, err)
+	}
+
+	if err := f.Parse([]string{"--port", "http", "--debug=maybe", "-px", "arg"}); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 80 || *debug || f.Changed("port") {
+		t.Errorf("expected the flags to keep their defaults, got %d %v", *port, *debug)
+	}
+	if !reflect.DeepEqual(f.Args(), []string{"arg"}) {
+		t.Errorf("unexpected args: %q", f.Args())
+	}
+	if len(warnings) != 3 {
+		t.Fatalf("expected 3 warnings, got %v", warnings)
+	}
+	invalid, ok := warnings[0].(*InvalidValueError) //nolint:errorlint
+	if !ok || invalid.GetFlag() != f.Lookup("port") || invalid.GetValue() != "http" {
+		t.Errorf("unexpected warning: %#v", warnings[0])
+	}
+
+	if err := f.Parse([]string{"--port"}); err == nil {
+		t.Error("expected missing values not to be allowed")
+	}
+}
+
+func TestAllowMissingValues(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	out := &bytes.Buffer{}
+	f.SetOutput(out)
+	port := f.IntP("port", "p", 80, "port")
+	f.ParseErrorsAllowlist.MissingValues = true
+
+	if err := f.Parse([]string{"--port"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Parse([]string{"-p"}); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 80 {
+		t.Errorf("expected the flag to keep its default, got %d", *port)
+	}
+	expected := "flag needs an argument: --port\nflag needs an argument: 'p' in -p\n"
+	if out.String() != expected {
+		t.Errorf("expected the warnings %q, got %q", expected, out.String())
+	}
+
+	if err := f.Parse([]string{"--port=x"}); err == nil {
+		t.Error("expected invalid values not to be allowed")
+	}
+}
+
+func TestAllowedErrorsResetToDefault(t *testing.T) {
+	tests := []struct {
+		args   []string
+		port   int
+		names  []string
+		labels map[string]string
+	}{
+		{[]string{"--port", "8080", "--port", "x"}, 80, []string{"a", "b"}, map[string]string{"env": "dev"}},
+		{[]string{"--port", "8080", "--port"}, 80, []string{"a", "b"}, map[string]string{"env": "dev"}},
+		{[]string{"--port", "x", "--port", "8080"}, 8080, []string{"a", "b"}, map[string]string{"env": "dev"}},
+		{[]string{"--name", "c", "--name"}, 80, []string{"a", "b"}, map[string]string{"env": "dev"}},
+		{[]string{"--label", "env=prod", "--label", "x"}, 80, []string{"a", "b"}, map[string]string{"env": "dev"}},
+		{[]string{"--label", "env=prod", "--label", "x", "--label", "k=v"}, 80, []string{"a", "b"}, map[string]string{"k": "v"}},
+	}
+	for _, test := range tests {
+		f := NewFlagSet("test", ContinueOnError)
+		f.SetOutput(&bytes.Buffer{})
+		port := f.Int("port", 80, "port")
+		names := f.StringSlice("name", []string{"a", "b"}, "names")
+		labels := f.StringToString("label", map[string]string{"env": "dev"}, "labels")
+		f.ParseErrorsAllowlist.InvalidValues = true
+		f.ParseErrorsAllowlist.MissingValues = true
+
+		if err := f.Parse(test.args); err != nil {
+			t.Fatalf("%q: %v", test.args, err)
+		}
+		if *port != test.port || !reflect.DeepEqual(*names, test.names) || !reflect.DeepEqual(*labels, test.labels) {
+			t.Errorf("%q: expected %d %q %v, got %d %q %v", test.args, test.port, test.names, test.labels, *port, *names, *labels)
+		}
+	}
+}
+
+func TestAllowedErrorsForgetFlag(t *testing.T) {
+	tests := []struct {
+		args    []string
+		changed []string
+		ints    []int
+	}{
+		{[]string{"--port=8080", "--port=x"}, nil, []int{1, 2}},
+		{[]string{"--port=8080", "--ints", "5", "--port"}, []string{"ints"}, []int{5}},
+		{[]string{"--ints", "5", "--ints", "x", "--ints", "7"}, []string{"ints"}, []int{7}},
+		{[]string{"--ints", "5", "--ints", "x"}, nil, []int{1, 2}},
+	}
+	for _, test := range tests {
+		f := NewFlagSet("test", ContinueOnError)
+		f.SetOutput(&bytes.Buffer{})
+		port := f.Int("port", 80, "port")
+		ints := f.IntSlice("ints", []int{1, 2}, "ints")
+		f.ParseErrorsAllowlist.InvalidValues = true
+		f.ParseErrorsAllowlist.MissingValues = true
+
+		if err := f.Parse(test.args); err != nil {
+			t.Fatalf("%q: %v", test.args, err)
+		}
+		var changed []string
+		f.Visit(func(flag *Flag) { changed = append(changed, flag.Name) })
+		if !reflect.DeepEqual(changed, test.changed) || f.NFlag() != len(test.changed) {
+			t.Errorf("%q: expected the changed flags %q, got %q", test.args, test.changed, changed)
+		}
+		if *port != 80 || f.Changed("port") || len(f.Occurrences("port")) != 0 {
+			t.Errorf("%q: expected port to be forgotten, got %d", test.args, *port)
+		}
+		if !reflect.DeepEqual(*ints, test.ints) {
+			t.Errorf("%q: expected the ints %v, got %v", test.args, test.ints, *ints)
+		}
+	}
+}
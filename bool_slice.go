@@ -57,6 +57,11 @@ func (s *boolSliceValue) Type() string {
 	return "boolSlice"
 }
 
+// clearChanged makes the next Set replace the value instead of adding to it.
+func (s *boolSliceValue) clearChanged() {
+	s.changed = false
+}
+
 // String defines a "native" format for this boolean slice flag value.
 func (s *boolSliceValue) String() string {
 	boolStrSlice := make([]string, len(*s.value))
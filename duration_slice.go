@@ -42,6 +42,11 @@ func (s *durationSliceValue) Type() string {
 	return "durationSlice"
 }
 
+// clearChanged makes the next Set replace the value instead of adding to it.
+func (s *durationSliceValue) clearChanged() {
+	s.changed = false
+}
+
 func (s *durationSliceValue) String() string {
 	out := make([]string, len(*s.value))
 	for i, d := range *s.value {
@@ -1,6 +1,10 @@
 package pflag
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 // notExistErrorMessageType specifies which flavor of "flag does not exist"
 // is printed by NotExistError. This allows the related errors to be grouped
@@ -21,23 +25,25 @@ type NotExistError struct {
 	name                string
 	specifiedShorthands string
 	messageType         notExistErrorMessageType
+	translator          Translator
 }
 
 // Error implements error.
 func (e *NotExistError) Error() string {
+	params := map[string]string{"name": e.name}
 	switch e.messageType {
 	case flagNotExistMessage:
-		return fmt.Sprintf("flag %q does not exist", e.name)
+		return e.translator.translate(MsgFlagNotExist, params)
 
 	case flagNoSuchFlagMessage:
-		return fmt.Sprintf("no such flag -%v", e.name)
+		return e.translator.translate(MsgNoSuchFlag, params)
 
 	case flagUnknownFlagMessage:
-		return fmt.Sprintf("unknown flag: --%s", e.name)
+		return e.translator.translate(MsgUnknownFlag, params)
 
 	case flagUnknownShorthandFlagMessage:
-		c := rune(e.name[0])
-		return fmt.Sprintf("unknown shorthand flag: %q in -%s", c, e.specifiedShorthands)
+		params["shorthands"] = e.specifiedShorthands
+		return e.translator.translate(MsgUnknownShorthandFlag, params)
 	}
 
 	panic(fmt.Errorf("unknown flagNotExistErrorMessageType: %v", e.messageType))
@@ -62,16 +68,19 @@ type ValueRequiredError struct {
 	flag                *Flag
 	specifiedName       string
 	specifiedShorthands string
+	translator          Translator
 }
 
 // Error implements error.
 func (e *ValueRequiredError) Error() string {
 	if len(e.specifiedShorthands) > 0 {
-		c := rune(e.specifiedName[0])
-		return fmt.Sprintf("flag needs an argument: %q in -%s", c, e.specifiedShorthands)
+		return e.translator.translate(MsgShorthandNeedsArgument, map[string]string{
+			"name":       e.specifiedName,
+			"shorthands": e.specifiedShorthands,
+		})
 	}
 
-	return fmt.Sprintf("flag needs an argument: --%s", e.specifiedName)
+	return e.translator.translate(MsgFlagNeedsArgument, map[string]string{"name": e.specifiedName})
 }
 
 // GetFlag returns the flag for which the error occurred.
@@ -95,9 +104,10 @@ func (e *ValueRequiredError) GetSpecifiedShortnames() string {
 // InvalidValueError is the error returned when an invalid value is used
 // for a flag.
 type InvalidValueError struct {
-	flag  *Flag
-	value string
-	cause error
+	flag       *Flag
+	value      string
+	cause      error
+	translator Translator
 }
 
 // Error implements error.
@@ -109,7 +119,11 @@ func (e *InvalidValueError) Error() string {
 	} else {
 		flagName = fmt.Sprintf("--%s", flag.Name)
 	}
-	return fmt.Sprintf("invalid argument %q for %q flag: %v", e.value, flagName, e.cause)
+	return e.translator.translate(MsgInvalidArgument, map[string]string{
+		"value": e.value,
+		"flag":  flagName,
+		"cause": fmt.Sprint(e.cause),
+	})
 }
 
 // Unwrap implements errors.Unwrap.
@@ -131,11 +145,12 @@ func (e *InvalidValueError) GetValue() string {
 // the command line.
 type InvalidSyntaxError struct {
 	specifiedFlag string
+	translator    Translator
 }
 
 // Error implements error.
 func (e *InvalidSyntaxError) Error() string {
-	return fmt.Sprintf("bad flag syntax: %s", e.specifiedFlag)
+	return e.translator.translate(MsgBadFlagSyntax, map[string]string{"flag": e.specifiedFlag})
 }
 
 // GetSpecifiedFlag returns the exact flag (with dashes) as it
@@ -143,3 +158,248 @@ func (e *InvalidSyntaxError) Error() string {
 func (e *InvalidSyntaxError) GetSpecifiedFlag() string {
 	return e.specifiedFlag
 }
+
+// ArityError is the error returned when the number of non-flag arguments
+// does not match the positional arguments defined in the FlagSet.
+type ArityError struct {
+	positional *Positional
+	minArgs    int
+	maxArgs    int
+	actual     int
+	translator Translator
+}
+
+// Error implements error.
+func (e *ArityError) Error() string {
+	if e.positional != nil {
+		return e.translator.translate(MsgMissingArgument, map[string]string{"name": e.positional.Name})
+	}
+	return e.translator.translate(MsgTooManyArguments, map[string]string{
+		"max":    strconv.Itoa(e.maxArgs),
+		"actual": strconv.Itoa(e.actual),
+	})
+}
+
+// GetPositional returns the missing positional argument, or nil if there
+// were too many arguments.
+func (e *ArityError) GetPositional() *Positional {
+	return e.positional
+}
+
+// GetMin returns the minimum number of arguments. It is only set if a
+// positional argument is missing.
+func (e *ArityError) GetMin() int {
+	return e.minArgs
+}
+
+// GetMax returns the maximum number of arguments. It is only set if there
+// were too many arguments.
+func (e *ArityError) GetMax() int {
+	return e.maxArgs
+}
+
+// GetActual returns the number of non-flag arguments given.
+func (e *ArityError) GetActual() int {
+	return e.actual
+}
+
+// InvalidPositionalError is the error returned when an invalid value is
+// given for a positional argument.
+type InvalidPositionalError struct {
+	positional *Positional
+	value      string
+	cause      error
+	translator Translator
+}
+
+// Error implements error.
+func (e *InvalidPositionalError) Error() string {
+	return e.translator.translate(MsgInvalidPositional, map[string]string{
+		"value": e.value,
+		"name":  e.positional.Name,
+		"cause": fmt.Sprint(e.cause),
+	})
+}
+
+// Unwrap implements errors.Unwrap.
+func (e *InvalidPositionalError) Unwrap() error {
+	return e.cause
+}
+
+// GetPositional returns the positional argument for which the error occurred.
+func (e *InvalidPositionalError) GetPositional() *Positional {
+	return e.positional
+}
+
+// GetValue returns the invalid value that was provided.
+func (e *InvalidPositionalError) GetValue() string {
+	return e.value
+}
+
+// UnknownCommandError is the error returned by Router.Run when the named
+// command does not exist.
+type UnknownCommandError struct {
+	name        string
+	suggestions []string
+	translator  Translator
+}
+
+// Error implements error.
+func (e *UnknownCommandError) Error() string {
+	msg := e.translator.translate(MsgUnknownCommand, map[string]string{"name": e.name})
+	if len(e.suggestions) == 0 {
+		return msg
+	}
+	return msg + "\n\n" + e.translator.translate(MsgDidYouMean, map[string]string{
+		"suggestions": strings.Join(e.suggestions, "\n\t"),
+	})
+}
+
+// GetSpecifiedName returns the name of the command as it appeared in the
+// parsed arguments.
+func (e *UnknownCommandError) GetSpecifiedName() string {
+	return e.name
+}
+
+// GetSuggestions returns the names of the commands close to the specified
+// one.
+func (e *UnknownCommandError) GetSuggestions() []string {
+	return e.suggestions
+}
+
+// RepeatedFlagError is the error returned when a flag which takes a single
+// value is given more than once and the repeat policy is RepeatError.
+type RepeatedFlagError struct {
+	flag       *Flag
+	first      Occurrence
+	second     Occurrence
+	translator Translator
+}
+
+// Error implements error.
+func (e *RepeatedFlagError) Error() string {
+	return e.translator.translate(MsgRepeatedFlag, map[string]string{
+		"name":   e.flag.Name,
+		"first":  e.first.String(),
+		"second": e.second.String(),
+	})
+}
+
+// GetFlag returns the flag which was repeated.
+func (e *RepeatedFlagError) GetFlag() *Flag {
+	return e.flag
+}
+
+// GetFirst returns the earlier occurrence of the flag.
+func (e *RepeatedFlagError) GetFirst() Occurrence {
+	return e.first
+}
+
+// GetSecond returns the occurrence which repeated the flag.
+func (e *RepeatedFlagError) GetSecond() Occurrence {
+	return e.second
+}
+
+// AmbiguousValueError is the error returned when the argument following a
+// flag with an optional value could be either its value or a non-flag
+// argument, and the flag's optional value policy is OptionalValueStrict.
+type AmbiguousValueError struct {
+	flag       *Flag
+	value      string
+	translator Translator
+}
+
+// Error implements error.
+func (e *AmbiguousValueError) Error() string {
+	return e.translator.translate(MsgAmbiguousValue, map[string]string{
+		"name":  e.flag.Name,
+		"value": e.value,
+	})
+}
+
+// GetFlag returns the flag with the optional value.
+func (e *AmbiguousValueError) GetFlag() *Flag {
+	return e.flag
+}
+
+// GetValue returns the argument following the flag.
+func (e *AmbiguousValueError) GetValue() string {
+	return e.value
+}
+
+// FlagRedefinedError is the error returned by TryAddFlag when a flag with
+// the same name is already defined.
+type FlagRedefinedError struct {
+	flagSetName string
+	flag        *Flag
+	existing    *Flag
+	translator  Translator
+}
+
+// Error implements error.
+func (e *FlagRedefinedError) Error() string {
+	return e.translator.translate(MsgFlagRedefined, map[string]string{
+		"flagset": e.flagSetName,
+		"name":    e.flag.Name,
+	})
+}
+
+// GetFlag returns the flag which could not be added.
+func (e *FlagRedefinedError) GetFlag() *Flag {
+	return e.flag
+}
+
+// GetExisting returns the flag already defined with the same name.
+func (e *FlagRedefinedError) GetExisting() *Flag {
+	return e.existing
+}
+
+// ShorthandConflictError is the error returned by TryAddFlag when another
+// flag already uses the same shorthand.
+type ShorthandConflictError struct {
+	flagSetName string
+	flag        *Flag
+	existing    *Flag
+	translator  Translator
+}
+
+// Error implements error.
+func (e *ShorthandConflictError) Error() string {
+	return e.translator.translate(MsgShorthandConflict, map[string]string{
+		"flagset":   e.flagSetName,
+		"shorthand": e.flag.Shorthand,
+		"name":      e.flag.Name,
+		"existing":  e.existing.Name,
+	})
+}
+
+// GetFlag returns the flag which could not be added.
+func (e *ShorthandConflictError) GetFlag() *Flag {
+	return e.flag
+}
+
+// GetExisting returns the flag already using the shorthand.
+func (e *ShorthandConflictError) GetExisting() *Flag {
+	return e.existing
+}
+
+// InvalidShorthandError is the error returned by TryAddFlag when the
+// shorthand of a flag is more than one character and multi-character
+// shorthands are not enabled.
+type InvalidShorthandError struct {
+	flag       *Flag
+	translator Translator
+}
+
+// Error implements error.
+func (e *InvalidShorthandError) Error() string {
+	return e.translator.translate(MsgInvalidShorthand, map[string]string{
+		"shorthand": e.flag.Shorthand,
+		"name":      e.flag.Name,
+	})
+}
+
+// GetFlag returns the flag which could not be added.
+func (e *InvalidShorthandError) GetFlag() *Flag {
+	return e.flag
+}
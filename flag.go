@@ -120,6 +120,7 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
 // ErrHelp is the error returned if the flag -help is invoked but no such flag is defined.
@@ -150,6 +151,12 @@ const (
 	// combined flags only with unknown ones.
 	// E.g. -fghi results -gh if only `f` and `i` are known.
 	PassUnknownFlagToArgs
+	// PassUnknownFlagToArgsInPlace will treat unknown flags as non-flag
+	// arguments like PassUnknownFlagToArgs, but combined shorthand flags are
+	// split at the known ones, so that the unknown ones keep their positions
+	// relative to the known ones.
+	// E.g. -fghij results -gh -j if only `f` and `i` are known.
+	PassUnknownFlagToArgsInPlace
 )
 
 // ParseErrorsAllowlist defines the parsing errors that can be ignored
@@ -160,6 +167,16 @@ type ParseErrorsAllowlist struct {
 
 	// UnknownFlagsHandling decides how to handle unknown flags. Defaults to UnknownFlagsHandlingErrorOnUnknown.
 	UnknownFlagsHandling UnknownFlagsHandling
+
+	// InvalidValues will ignore flags given a value they reject; the flags are reset to their default value, as if they had not been given
+	InvalidValues bool
+
+	// MissingValues will ignore flags given without the value they require; the flags are reset to their default value, as if they had not been given
+	MissingValues bool
+
+	// Warn is called with every error ignored because of InvalidValues or MissingValues,
+	// an *InvalidValueError or a *ValueRequiredError. If nil, the errors are printed to the output
+	Warn func(err error)
 }
 
 // ParseErrorsWhitelist defines the parsing errors that can be ignored.
@@ -198,14 +215,32 @@ type FlagSet struct {
 	formal            map[NormalizedName]*Flag
 	orderedFormal     []*Flag
 	sortedFormal      []*Flag
-	shorthands        map[byte]*Flag
+	shorthands        map[string]*Flag
 	args              []string // arguments after flags
 	argsLenAtDash     int      // len(args) when a '--' was located when parsing, or -1 if no --
 	errorHandling     ErrorHandling
 	output            io.Writer // nil means stderr; use Output() accessor
 	interspersed      bool      // allow interspersed option/non-option args
 	normalizeNameFunc func(f *FlagSet, name string) NormalizedName
-
+	groups            []string       // usage groups in registration order
+	groupAlignment    GroupAlignment // how usage columns are aligned across groups
+	usageFormatter    UsageFormatter // nil means DefaultUsageFormatter
+	usageWidth        int            // columns PrintDefaults wraps to; see SetUsageWidth
+	translator        Translator     // nil means default English messages
+	theme             *Theme         // nil means unstyled usage output
+	colorMode         ColorMode      // when usage output is styled with theme
+	positionals       []*Positional  // positional arguments in binding order
+	repeatPolicy      RepeatPolicy   // what happens when a single-valued flag is repeated
+	syntax            Syntax         // flag syntaxes accepted in addition to the GNU one
+	negativeNumbers   bool           // negative numbers are non-flag arguments
+	returnInOrder     bool           // non-flag arguments are passed to the parse function
+	multiShorthands   bool           // shorthands may be more than one character
+	maxShorthandLen   int            // length in bytes of the longest shorthand
+
+	occurrences     map[NormalizedName][]Occurrence // flags given to the last Parse, by name
+	unknownFlags    []UnknownFlag                   // unknown flags given to the last Parse
+	passedArgs      map[int]bool                    // indices in args of the unknown flags passed to them
+	removed         map[NormalizedName]*Flag        // flags removed with RemoveFlag
 	addedGoFlagSets []*goflag.FlagSet
 }
 
@@ -222,6 +257,13 @@ type Flag struct {
 	Hidden              bool                // used by cobra.Command to allow flags to be hidden from help/usage text
 	ShorthandDeprecated string              // If the shorthand of this flag is deprecated, this string is the new or now thing to use
 	Annotations         map[string][]string // used by cobra.Command bash autocomple code
+	Group               string              // usage group the flag is listed under; empty for none
+	OptionalValuePolicy OptionalValuePolicy // whether a flag with a NoOptDefVal takes the next argument as its value
+	AcceptOptionalValue func(string) bool   // which next arguments can be the optional value; nil for those not starting with a dash
+
+	namespace string   // namespace this flag was added under by AddNamespace
+	origin    *Flag    // flag this one was created from by AddNamespace
+	originSet *FlagSet // FlagSet of origin
 }
 
 // Value is the interface to the dynamic value stored in a flag.
@@ -429,18 +471,18 @@ func (f *FlagSet) Lookup(name string) *Flag {
 
 // ShorthandLookup returns the Flag structure of the short handed flag,
 // returning nil if none exists.
-// It panics, if len(name) > 1.
+// It panics, if name is more than one character and multi-character
+// shorthands are not enabled with SetMultiCharShorthands.
 func (f *FlagSet) ShorthandLookup(name string) *Flag {
 	if name == "" {
 		return nil
 	}
-	if len(name) > 1 {
+	if utf8.RuneCountInString(name) > 1 && !f.multiShorthands {
 		msg := fmt.Sprintf("can not look up shorthand which is more than one ASCII character: %q", name)
 		_, _ = fmt.Fprint(f.Output(), msg)
 		panic(msg)
 	}
-	c := name[0]
-	return f.shorthands[c]
+	return f.shorthands[name]
 }
 
 // lookup returns the Flag structure of the named flag, returning nil if none exists.
@@ -457,7 +499,7 @@ func (f *FlagSet) lookup(name NormalizedName) *Flag {
 func (f *FlagSet) getFlagType(name string, ftype string, convFunc func(sval string) (interface{}, error)) (interface{}, error) {
 	flag := f.Lookup(name)
 	if flag == nil {
-		err := &NotExistError{name: name, messageType: flagNotExistMessage}
+		err := f.translateError(&NotExistError{name: name, messageType: flagNotExistMessage})
 		return nil, err
 	}
 
@@ -485,13 +527,36 @@ func (f *FlagSet) ArgsLenAtDash() int {
 	return f.argsLenAtDash
 }
 
+// ArgsSegments returns the non-flag arguments split at each "--". The first
+// segment holds the arguments before the first "--", and there is one more
+// segment for each "--" found, e.g. for "tool --opt a -- child1 x -- child2 y"
+// it returns [a] [child1 x] [child2 y]. This holds whether the first "--"
+// terminates the flags or, with SetInterspersed(false), follows the non-flag
+// argument parsing stopped at, as in "tool --opt child1 x -- child2 y".
+// Without any "--" there is a single segment, equal to Args().
+func (f *FlagSet) ArgsSegments() [][]string {
+	var segments [][]string
+	start := 0
+	if f.argsLenAtDash >= 0 {
+		segments = append(segments, f.args[:f.argsLenAtDash])
+		start = f.argsLenAtDash
+	}
+	for i := start; i < len(f.args); i++ {
+		if f.args[i] == "--" {
+			segments = append(segments, f.args[start:i])
+			start = i + 1
+		}
+	}
+	return append(segments, f.args[start:])
+}
+
 // MarkDeprecated indicated that a flag is deprecated in your program. It will
 // continue to function but will not show up in help or usage messages. Using
 // this flag will also print the given usageMessage.
 func (f *FlagSet) MarkDeprecated(name string, usageMessage string) error {
 	flag := f.Lookup(name)
 	if flag == nil {
-		return &NotExistError{name: name, messageType: flagNotExistMessage}
+		return f.translateError(&NotExistError{name: name, messageType: flagNotExistMessage})
 	}
 	if usageMessage == "" {
 		return fmt.Errorf("deprecated message for flag %q must be set", name)
@@ -507,7 +572,7 @@ func (f *FlagSet) MarkDeprecated(name string, usageMessage string) error {
 func (f *FlagSet) MarkShorthandDeprecated(name string, usageMessage string) error {
 	flag := f.Lookup(name)
 	if flag == nil {
-		return &NotExistError{name: name, messageType: flagNotExistMessage}
+		return f.translateError(&NotExistError{name: name, messageType: flagNotExistMessage})
 	}
 	if usageMessage == "" {
 		return fmt.Errorf("deprecated message for flag %q must be set", name)
@@ -521,7 +586,7 @@ func (f *FlagSet) MarkShorthandDeprecated(name string, usageMessage string) erro
 func (f *FlagSet) MarkHidden(name string) error {
 	flag := f.Lookup(name)
 	if flag == nil {
-		return &NotExistError{name: name, messageType: flagNotExistMessage}
+		return f.translateError(&NotExistError{name: name, messageType: flagNotExistMessage})
 	}
 	flag.Hidden = true
 	return nil
@@ -544,18 +609,39 @@ func (f *FlagSet) Set(name, value string) error {
 	normalName := f.normalizeFlagName(name)
 	flag, ok := f.formal[normalName]
 	if !ok {
-		return &NotExistError{name: name, messageType: flagNoSuchFlagMessage}
+		return f.translateError(&NotExistError{name: name, messageType: flagNoSuchFlagMessage})
 	}
 
 	err := flag.Value.Set(value)
 	if err != nil {
-		return &InvalidValueError{
+		return f.translateError(&InvalidValueError{
 			flag:  flag,
 			value: value,
 			cause: err,
+		})
+	}
+
+	f.markChanged(normalName, flag)
+	for fl := flag; fl.origin != nil; fl = fl.origin {
+		// a flag added with AddNamespace, unless its origin was removed since
+		originName := fl.originSet.normalizeFlagName(fl.origin.Name)
+		if fl.originSet.formal[originName] != fl.origin {
+			break
 		}
+		fl.originSet.markChanged(originName, fl.origin)
 	}
 
+	if flag.Deprecated != "" {
+		_, _ = fmt.Fprintln(f.Output(), f.translator.translate(MsgFlagDeprecated, map[string]string{
+			"name":    flag.Name,
+			"message": flag.Deprecated,
+		}))
+	}
+	return nil
+}
+
+// markChanged records that the flag was set.
+func (f *FlagSet) markChanged(normalName NormalizedName, flag *Flag) {
 	if !flag.Changed {
 		if f.actual == nil {
 			f.actual = make(map[NormalizedName]*Flag)
@@ -565,11 +651,6 @@ func (f *FlagSet) Set(name, value string) error {
 
 		flag.Changed = true
 	}
-
-	if flag.Deprecated != "" {
-		_, _ = fmt.Fprintf(f.Output(), "Flag --%s has been deprecated, %s\n", flag.Name, flag.Deprecated)
-	}
-	return nil
 }
 
 // SetAnnotation allows one to set arbitrary annotations on a flag in the FlagSet.
@@ -579,7 +660,7 @@ func (f *FlagSet) SetAnnotation(name, key string, values []string) error {
 	normalName := f.normalizeFlagName(name)
 	flag, ok := f.formal[normalName]
 	if !ok {
-		return &NotExistError{name: name, messageType: flagNoSuchFlagMessage}
+		return f.translateError(&NotExistError{name: name, messageType: flagNoSuchFlagMessage})
 	}
 	if flag.Annotations == nil {
 		flag.Annotations = map[string][]string{}
@@ -606,8 +687,9 @@ func Set(name, value string) error {
 
 // PrintDefaults prints, to standard error unless configured
 // otherwise, the default values of all defined flags in the set.
+// Usages are wrapped to the width set with SetUsageWidth.
 func (f *FlagSet) PrintDefaults() {
-	usages := f.FlagUsages()
+	usages := f.FlagUsagesWrapped(f.usageWidth)
 	_, _ = fmt.Fprint(f.Output(), usages)
 }
 
@@ -694,19 +776,20 @@ func UnquoteUsage(flag *Flag) (name string, usage string) {
 }
 
 // Splits the string `s` on whitespace into an initial substring up to
-// `i` runes in length and the remainder. Will go `slop` over `i` if
-// that encompasses the entire string (which allows the caller to
+// `i` columns in display width and the remainder. Will go `slop` over
+// `i` if that encompasses the entire string (which allows the caller to
 // avoid short orphan words on the final line).
 func wrapN(i, slop int, s string) (string, string) {
-	if i+slop > len(s) {
+	if i+slop > displayWidth(s) {
 		return s, ""
 	}
 
-	w := strings.LastIndexAny(s[:i], " \t\n")
+	n := widthOffset(s, i)
+	w := strings.LastIndexAny(s[:n], " \t\n")
 	if w <= 0 {
 		return s, ""
 	}
-	nlPos := strings.LastIndex(s[:i], "\n")
+	nlPos := strings.LastIndex(s[:n], "\n")
 	if nlPos > 0 && nlPos < w {
 		return s[:nlPos], s[nlPos+1:]
 	}
@@ -763,76 +846,56 @@ func wrap(i, w int, s string) string {
 
 // FlagUsagesWrapped returns a string containing the usage information
 // for all flags in the FlagSet. Wrapped to `cols` columns (0 for no
-// wrapping)
+// wrapping, TerminalWidth for the width of the terminal Output() is
+// connected to). Flags assigned to a usage group are listed after the
+// ungrouped flags, under a header per group.
 func (f *FlagSet) FlagUsagesWrapped(cols int) string {
+	if cols == TerminalWidth {
+		cols = terminalWidth(f.Output())
+	}
+
 	buf := new(bytes.Buffer)
+	formatter := f.GetUsageFormatter()
+	theme := f.activeTheme()
 
-	lines := make([]string, 0, len(f.formal))
+	groups := f.usageGroups()
+	usages := make([][]FlagUsage, len(groups))
+	names := make([][]string, len(groups))
+	maxlens := make([]int, len(groups))
 
 	maxlen := 0
-	f.VisitAll(func(flag *Flag) {
-		if flag.Hidden {
-			return
-		}
-
-		line := ""
-		if flag.Shorthand != "" && flag.ShorthandDeprecated == "" {
-			line = fmt.Sprintf("  -%s, --%s", flag.Shorthand, flag.Name)
-		} else {
-			line = fmt.Sprintf("      --%s", flag.Name)
-		}
-
-		varname, usage := UnquoteUsage(flag)
-		if isNoOptBoolValue(flag.Value) && flag.Value.Type() == "bool" {
-			line += "[=true|false]"
-		} else if varname != "" {
-			line += " " + varname
-		}
-
-		if flag.NoOptDefVal != "" {
-			switch flag.Value.Type() {
-			case "string":
-				line += fmt.Sprintf("[=\"%s\"]", flag.NoOptDefVal)
-			case "bool", "boolfunc":
-				if flag.NoOptDefVal != "true" {
-					line += fmt.Sprintf("[=%s]", flag.NoOptDefVal)
-				}
-			case "count":
-				if flag.NoOptDefVal != "+1" {
-					line += fmt.Sprintf("[=%s]", flag.NoOptDefVal)
-				}
-			default:
-				line += fmt.Sprintf("[=%s]", flag.NoOptDefVal)
+	for i, group := range groups {
+		for _, flag := range group.flags {
+			u := newFlagUsage(flag)
+			u.Translator = f.translator
+			u.Theme = theme
+			n := formatter.FlagNames(u)
+			if w := displayWidth(n); w > maxlens[i] {
+				maxlens[i] = w
 			}
+			usages[i] = append(usages[i], u)
+			names[i] = append(names[i], n)
 		}
-
-		// This special character will be replaced with spacing once the
-		// correct alignment is calculated
-		line += "\x00"
-		if len(line) > maxlen {
-			maxlen = len(line)
+		if maxlens[i] > maxlen {
+			maxlen = maxlens[i]
 		}
+	}
 
-		line += usage
-		if !flag.defaultIsZeroValue() {
-			if flag.Value.Type() == "string" {
-				line += fmt.Sprintf(" (default %q)", flag.DefValue)
-			} else {
-				line += fmt.Sprintf(" (default %s)", flag.DefValue)
+	for i, group := range groups {
+		if group.name != "" {
+			if buf.Len() > 0 {
+				_, _ = fmt.Fprintln(buf)
 			}
+			t := theme.orPlain()
+			_, _ = fmt.Fprintf(buf, "%s:\n", t.style(t.GroupHeader, group.name))
 		}
-		if len(flag.Deprecated) != 0 {
-			line += fmt.Sprintf(" (DEPRECATED: %s)", flag.Deprecated)
+		if f.groupAlignment == AlignPerGroup {
+			maxlen = maxlens[i]
+		}
+		widths := UsageWidths{Names: maxlen, Cols: cols}
+		for j, u := range usages[i] {
+			buf.WriteString(formatter.FormatFlag(u, names[i][j], widths))
 		}
-
-		lines = append(lines, line)
-	})
-
-	for _, line := range lines {
-		sidx := strings.Index(line, "\x00")
-		spacing := strings.Repeat(" ", maxlen-sidx)
-		// maxlen + 2 comes from + 1 for the \x00 and + 1 for the (deliberate) off-by-one in maxlen-sidx
-		_, _ = fmt.Fprintln(buf, line[:sidx], spacing, wrap(maxlen+2, cols, line[sidx+1:]))
 	}
 
 	return buf.String()
@@ -851,8 +914,14 @@ func PrintDefaults() {
 
 // defaultUsage is the default function to print a usage message.
 func defaultUsage(f *FlagSet) {
-	_, _ = fmt.Fprintf(f.Output(), "Usage of %s:\n", f.name)
+	_, _ = fmt.Fprintln(f.Output(), f.translator.translate(MsgUsageOf, map[string]string{"name": f.name}))
+	if len(f.positionals) == 0 {
+		f.PrintDefaults()
+		return
+	}
+	_, _ = fmt.Fprintf(f.Output(), "  %s\n\n", f.Synopsis())
 	f.PrintDefaults()
+	_, _ = fmt.Fprintf(f.Output(), "\n%s\n%s", f.translator.translate(MsgUsageArguments, nil), f.PositionalUsages())
 }
 
 // NOTE: Usage is not just defaultUsage(CommandLine)
@@ -932,14 +1001,26 @@ func (f *FlagSet) VarP(value Value, name, shorthand, usage string) {
 
 // AddFlag will add the flag to the FlagSet
 func (f *FlagSet) AddFlag(flag *Flag) {
-	normalizedFlagName := f.normalizeFlagName(flag.Name)
+	if err := f.TryAddFlag(flag); err != nil {
+		msg := err.Error()
+		if _, redefined := err.(*FlagRedefinedError); redefined { //nolint:errorlint
+			_, _ = fmt.Fprintln(f.Output(), msg)
+		} else {
+			_, _ = fmt.Fprint(f.Output(), msg)
+		}
+		panic(msg) // Happens only if flags are declared with identical names or shorthands
+	}
+}
 
-	_, alreadyThere := f.formal[normalizedFlagName]
-	if alreadyThere {
-		msg := fmt.Sprintf("%s flag redefined: %s", f.name, flag.Name)
-		_, _ = fmt.Fprintln(f.Output(), msg)
-		panic(msg) // Happens only if flags are declared with identical names
+// TryAddFlag will add the flag to the FlagSet, like AddFlag, but returns a
+// *FlagRedefinedError, *ShorthandConflictError or *InvalidShorthandError
+// instead of panicking. The FlagSet is unchanged if an error is returned.
+func (f *FlagSet) TryAddFlag(flag *Flag) error {
+	normalizedFlagName := f.normalizeFlagName(flag.Name)
+	if err := f.checkNewFlag(flag, normalizedFlagName, f.formal, f.shorthands); err != nil {
+		return err
 	}
+
 	if f.formal == nil {
 		f.formal = make(map[NormalizedName]*Flag)
 	}
@@ -952,37 +1033,90 @@ func (f *FlagSet) AddFlag(flag *Flag) {
 	f.orderedFormal = append(f.orderedFormal, flag)
 
 	if flag.Shorthand == "" {
-		return
-	}
-	if len(flag.Shorthand) > 1 {
-		msg := fmt.Sprintf("%q shorthand is more than one ASCII character", flag.Shorthand)
-		_, _ = fmt.Fprint(f.Output(), msg)
-		panic(msg)
+		return nil
 	}
 	if f.shorthands == nil {
-		f.shorthands = make(map[byte]*Flag)
+		f.shorthands = make(map[string]*Flag)
 	}
-	c := flag.Shorthand[0]
-	used, alreadyThere := f.shorthands[c]
-	if alreadyThere {
-		msg := fmt.Sprintf("unable to redefine %q shorthand in %q flagset: it's already used for %q flag", c, f.name, used.Name)
-		_, _ = fmt.Fprint(f.Output(), msg)
-		panic(msg)
+	f.shorthands[flag.Shorthand] = flag
+	if len(flag.Shorthand) > f.maxShorthandLen {
+		f.maxShorthandLen = len(flag.Shorthand)
+	}
+	return nil
+}
+
+// checkNewFlag returns the error TryAddFlag returns if flag, whose name
+// normalizes to name, cannot be added to a FlagSet with the given flags and
+// shorthands.
+func (f *FlagSet) checkNewFlag(flag *Flag, name NormalizedName, formal map[NormalizedName]*Flag, shorthands map[string]*Flag) error {
+	if existing, alreadyThere := formal[name]; alreadyThere {
+		return f.translateError(&FlagRedefinedError{flagSetName: f.name, flag: flag, existing: existing})
+	}
+	if flag.Shorthand != "" {
+		if utf8.RuneCountInString(flag.Shorthand) > 1 && !f.multiShorthands {
+			return f.translateError(&InvalidShorthandError{flag: flag})
+		}
+		if used, alreadyThere := shorthands[flag.Shorthand]; alreadyThere {
+			return f.translateError(&ShorthandConflictError{flagSetName: f.name, flag: flag, existing: used})
+		}
 	}
-	f.shorthands[c] = flag
+	return nil
+}
+
+// TryVarP is like VarP, but returns an error instead of panicking if the
+// flag cannot be added. See TryAddFlag.
+func (f *FlagSet) TryVarP(value Value, name, shorthand, usage string) error {
+	return f.TryAddFlag(&Flag{
+		Name:      name,
+		Shorthand: shorthand,
+		Usage:     usage,
+		Value:     value,
+		DefValue:  value.String(),
+	})
 }
 
 // AddFlagSet adds one FlagSet to another. If a flag is already present in f
-// the flag from newSet will be ignored.
+// the flag from newSet will be ignored. The usage groups of the flags added
+// are registered in f after its own.
 func (f *FlagSet) AddFlagSet(newSet *FlagSet) {
 	if newSet == nil {
 		return
 	}
+	var added []*Flag
 	newSet.VisitAll(func(flag *Flag) {
 		if f.Lookup(flag.Name) == nil {
 			f.AddFlag(flag)
+			added = append(added, flag)
 		}
 	})
+	f.addGroupsOf(newSet, added)
+}
+
+// TryAddFlagSet adds the flags of newSet to f, like AddFlagSet, but reports
+// conflicts instead of ignoring flags or panicking. Every flag which can be
+// added is added, and an error is returned for each one which cannot: a
+// *FlagRedefinedError for a flag whose name is already used by another
+// flag, or a *ShorthandConflictError for a flag whose shorthand is. Flags
+// already present in f, as when the same flags are added again, are skipped
+// without error.
+func (f *FlagSet) TryAddFlagSet(newSet *FlagSet) []error {
+	if newSet == nil {
+		return nil
+	}
+	var added []*Flag
+	var errs []error
+	newSet.VisitAll(func(flag *Flag) {
+		if f.Lookup(flag.Name) == flag {
+			return
+		}
+		if err := f.TryAddFlag(flag); err != nil {
+			errs = append(errs, err)
+			return
+		}
+		added = append(added, flag)
+	})
+	f.addGroupsOf(newSet, added)
+	return errs
 }
 
 // Var defines a flag with the specified name and usage string. The type and
@@ -1003,6 +1137,7 @@ func VarP(value Value, name, shorthand, usage string) {
 // fail prints an error message and usage message to standard error and
 // returns the error.
 func (f *FlagSet) fail(err error) error {
+	err = f.translateError(err)
 	if f.errorHandling != ContinueOnError {
 		f.usage()
 	}
@@ -1054,38 +1189,61 @@ func (e *unknownFlagError) Error() string {
 	return fmt.Sprintf("unknown flag: %v", e.UnknownFlags)
 }
 
-func (f *FlagSet) parseLongArg(s string, args []string, fn parseFunc) (a []string, err error) {
-	a = args
+func (tk *tokenizer) parseLongArg(s string, args []string) (a []string, err error) {
 	name := s[2:]
 	if len(name) == 0 || name[0] == '-' || name[0] == '=' {
-		err = f.fail(&InvalidSyntaxError{specifiedFlag: s})
-		return
+		return args, &InvalidSyntaxError{specifiedFlag: s}
 	}
+	return tk.parseLongName(s, name, args)
+}
 
+// parseLongName reads the long flag named by name, optionally followed by
+// "=value", from the argument s.
+func (tk *tokenizer) parseLongName(s, name string, args []string) (a []string, err error) {
+	f := tk.f
+	a = args
 	split := strings.SplitN(name, "=", 2)
 	name = split[0]
 	flag, exists := f.formal[f.normalizeFlagName(name)]
 	unknownFlagsHandling := f.getUnknownFlagsHandling()
+	token := Token{Kind: LongFlagToken, Index: tk.index, Arg: s, Name: name, Flag: flag, ValueIndex: tk.index}
 
 	if !exists {
+		token.Kind = UnknownFlagToken
 		switch {
 		case name == "help":
-			f.usage()
 			return a, ErrHelp
 		case unknownFlagsHandling == IgnoreUnknownFlag:
 			// --unknown=unknownval arg ...
 			// we do not want to lose arg in this case
 			if len(split) >= 2 {
+				token.Value, token.ValueOrigin = split[1], InlineValue
+				tk.emit(token)
 				return a, nil
 			}
 
-			return stripUnknownFlagValue(a), nil
-		case unknownFlagsHandling == PassUnknownFlagToArgs:
-			return a, &unknownFlagError{
-				UnknownFlags: s,
+			a = stripUnknownFlagValue(a)
+			if len(a) < len(args) {
+				token.Value, token.ValueOrigin, token.ValueIndex = args[0], NextArgValue, tk.index+1
+			}
+			tk.emit(token)
+			return a, nil
+		case unknownFlagsHandling == PassUnknownFlagToArgs || unknownFlagsHandling == PassUnknownFlagToArgsInPlace:
+			if len(split) >= 2 {
+				token.Value, token.ValueOrigin = split[1], InlineValue
 			}
+			token.passed = s
+			tk.emit(token)
+			return a, nil
 		default:
-			err = f.fail(&NotExistError{name: name, messageType: flagUnknownFlagMessage})
+			err = &NotExistError{name: name, messageType: flagUnknownFlagMessage}
+			return
+		}
+	}
+
+	next := false
+	if len(split) < 2 {
+		if next, err = f.takesNextArg(flag, a); err != nil {
 			return
 		}
 	}
@@ -1094,127 +1252,155 @@ func (f *FlagSet) parseLongArg(s string, args []string, fn parseFunc) (a []strin
 	if len(split) == 2 {
 		// '--flag=arg'
 		value = split[1]
+		token.ValueOrigin = InlineValue
+	} else if next {
+		// '--flag arg' (arg was optional)
+		value = a[0]
+		a = a[1:]
+		token.ValueOrigin, token.ValueIndex = NextArgValue, tk.index+1
 	} else if flag.NoOptDefVal != "" {
 		// '--flag' (arg was optional)
 		value = flag.NoOptDefVal
+		token.ValueOrigin = NoOptDefValue
 	} else if len(a) > 0 {
 		// '--flag arg'
 		value = a[0]
 		a = a[1:]
+		token.ValueOrigin, token.ValueIndex = NextArgValue, tk.index+1
 	} else {
 		// '--flag' (arg was required)
-		err = f.fail(&ValueRequiredError{
+		err = &ValueRequiredError{
 			flag:          flag,
 			specifiedName: name,
-		})
+		}
 		return
 	}
 
-	err = fn(flag, value)
-	if err != nil {
-		err = f.fail(err)
-	}
+	token.Value = value
+	tk.emit(token)
 	return
 }
 
-func (f *FlagSet) parseSingleShortArg(shorthands string, args []string, fn parseFunc) (outShorts string, outArgs []string, err error) {
+func (tk *tokenizer) parseSingleShortArg(shorthands string, args []string) (outShorts string, outArgs []string, err error) {
+	f := tk.f
 	outArgs = args
 
 	if isGotestShorthandFlag(shorthands) {
 		return
 	}
 
-	outShorts = shorthands[1:]
-	c := shorthands[0]
+	c := f.nextShorthand(shorthands)
+	outShorts = shorthands[len(c):]
 
 	flag, exists := f.shorthands[c]
+	token := Token{Kind: ShortFlagToken, Index: tk.index, Arg: tk.arg, Name: c, Flag: flag, ValueIndex: tk.index}
 	if !exists {
 		unknownFlagsHandling := f.getUnknownFlagsHandling()
+		token.Kind = UnknownFlagToken
 
 		switch {
-		case c == 'h':
-			f.usage()
+		case c == "h":
 			err = ErrHelp
 			return
 		case unknownFlagsHandling == IgnoreUnknownFlag:
 			// '-f=arg arg ...'
 			// we do not want to lose arg in this case
-			if len(shorthands) > 2 && shorthands[1] == '=' {
+			if len(outShorts) > 1 && outShorts[0] == '=' {
+				token.Value, token.ValueOrigin = outShorts[1:], InlineValue
 				outShorts = ""
+				tk.emit(token)
 				return
 			}
 
 			outArgs = stripUnknownFlagValue(outArgs)
+			if len(outArgs) < len(args) {
+				token.Value, token.ValueOrigin, token.ValueIndex = args[0], NextArgValue, tk.index+1
+			}
+			tk.emit(token)
 			return
-		case unknownFlagsHandling == PassUnknownFlagToArgs:
+		case unknownFlagsHandling == PassUnknownFlagToArgs || unknownFlagsHandling == PassUnknownFlagToArgsInPlace:
 			// '-f=arg': pass all the argument
-			if len(shorthands) > 2 && shorthands[1] == '=' {
+			if len(outShorts) > 1 && outShorts[0] == '=' {
+				token.Value, token.ValueOrigin = outShorts[1:], InlineValue
 				outShorts = ""
+				tk.emit(token)
 				err = &unknownFlagError{
 					UnknownFlags: shorthands,
 				}
 				return
 			}
 			// '-fgh': pass only the first switch
+			tk.emit(token)
 			err = &unknownFlagError{
-				UnknownFlags: shorthands[0:1],
+				UnknownFlags: c,
 			}
 			return
 		default:
-			err = f.fail(&NotExistError{
-				name:                string(c),
+			err = &NotExistError{
+				name:                c,
 				specifiedShorthands: shorthands,
 				messageType:         flagUnknownShorthandFlagMessage,
-			})
+			}
+			return
+		}
+	}
+
+	next := false
+	if len(outShorts) == 0 {
+		if next, err = f.takesNextArg(flag, args); err != nil {
 			return
 		}
 	}
 
 	var value string
-	if len(shorthands) > 2 && shorthands[1] == '=' {
+	if next {
+		// '-f arg' (arg was optional)
+		value = args[0]
+		outArgs = args[1:]
+		token.ValueOrigin, token.ValueIndex = NextArgValue, tk.index+1
+	} else if len(outShorts) > 1 && outShorts[0] == '=' {
 		// '-f=arg'
-		value = shorthands[2:]
+		value = outShorts[1:]
 		outShorts = ""
+		token.ValueOrigin = InlineValue
 	} else if flag.NoOptDefVal != "" {
 		// '-f' (arg was optional)
 		value = flag.NoOptDefVal
-	} else if len(shorthands) > 1 {
+		token.ValueOrigin = NoOptDefValue
+	} else if len(outShorts) > 0 {
 		// '-farg'
-		value = shorthands[1:]
+		value = outShorts
 		outShorts = ""
+		token.ValueOrigin = InlineValue
 	} else if len(args) > 0 {
 		// '-f arg'
 		value = args[0]
 		outArgs = args[1:]
+		token.ValueOrigin, token.ValueIndex = NextArgValue, tk.index+1
 	} else {
 		// '-f' (arg was required)
-		err = f.fail(&ValueRequiredError{
+		err = &ValueRequiredError{
 			flag:                flag,
-			specifiedName:       string(c),
+			specifiedName:       c,
 			specifiedShorthands: shorthands,
-		})
+		}
 		return
 	}
 
-	if flag.ShorthandDeprecated != "" {
-		_, _ = fmt.Fprintf(f.Output(), "Flag shorthand -%s has been deprecated, %s\n", flag.Shorthand, flag.ShorthandDeprecated)
-	}
-
-	err = fn(flag, value)
-	if err != nil {
-		err = f.fail(err)
-	}
+	token.Value = value
+	tk.emit(token)
 	return
 }
 
-func (f *FlagSet) parseShortArg(s string, args []string, fn parseFunc) (a []string, err error) {
+func (tk *tokenizer) parseShortArg(s string, args []string) (a []string, err error) {
 	a = args
 	shorthands := s[1:]
 	var errUnknownFlagAll *unknownFlagError
+	lastUnknown := -1 // index in tk.tokens of the last unknown flag
 
 	// "shorthands" can be a series of shorthand letters of flags (e.g. "-vvv").
 	for len(shorthands) > 0 {
-		shorthands, a, err = f.parseSingleShortArg(shorthands, args, fn)
+		shorthands, a, err = tk.parseSingleShortArg(shorthands, args)
 		if err != nil {
 			if errUnknownFlag, ok := err.(*unknownFlagError); ok { //nolint:errorlint
 				// this means f.ParseErrorsAllowlist.UnknownFlagsHandling is set to UnknownFlagsHandlingPassUnknownToArgs
@@ -1226,56 +1412,125 @@ func (f *FlagSet) parseShortArg(s string, args []string, fn parseFunc) (a []stri
 
 				errUnknownFlagAll.UnknownFlags = errUnknownFlagAll.UnknownFlags +
 					errUnknownFlag.UnknownFlags
+				lastUnknown = len(tk.tokens) - 1
 				err = nil
 			} else {
 				return
 			}
+		} else if errUnknownFlagAll != nil && tk.f.getUnknownFlagsHandling() == PassUnknownFlagToArgsInPlace {
+			// a known flag ends the run of unknown ones before it
+			tk.tokens[lastUnknown].passed = errUnknownFlagAll.UnknownFlags
+			errUnknownFlagAll = nil
 		}
 	}
 	if errUnknownFlagAll != nil {
-		err = errUnknownFlagAll
+		tk.tokens[lastUnknown].passed = errUnknownFlagAll.UnknownFlags
 	}
 
 	return
 }
 
-func (f *FlagSet) parseArgs(args []string, fn parseFunc) (err error) {
+func (tk *tokenizer) parseArgs(args []string) (err error) {
+	f := tk.f
+	total := len(args)
 	for len(args) > 0 {
 		s := args[0]
 		args = args[1:]
-		if len(s) == 0 || s[0] != '-' || len(s) == 1 {
+		tk.index = total - len(args) - 1
+		tk.arg = s
+		switch {
+		case f.isSlashFlag(s):
+			args, err = tk.parseSlashArg(s, args)
+		case len(s) == 0 || s[0] != '-' || len(s) == 1 || f.isNegativeNumber(s):
 			if !f.interspersed {
-				f.args = append(f.args, s)
-				f.args = append(f.args, args...)
+				tk.emitPositionals(append([]string{s}, args...)...)
 				return nil
 			}
-			f.args = append(f.args, s)
-			continue
-		}
-
-		if s[1] == '-' {
+			tk.emitPositionals(s)
+		case s[1] == '-':
 			if len(s) == 2 { // "--" terminates the flags
-				f.argsLenAtDash = len(f.args)
-				f.args = append(f.args, args...)
-				break
+				tk.emit(Token{Kind: TerminatorToken, Index: tk.index, Arg: s, ValueIndex: tk.index})
+				tk.index++
+				tk.emitPositionals(args...)
+				return nil
 			}
-			args, err = f.parseLongArg(s, args, fn)
-		} else {
-			args, err = f.parseShortArg(s, args, fn)
+			args, err = tk.parseLongArg(s, args)
+		case f.isSingleDashLongFlag(s):
+			args, err = tk.parseLongName(s, s[1:], args)
+		default:
+			args, err = tk.parseShortArg(s, args)
 		}
 		if err != nil {
-			if errUnknownFlag, ok := err.(*unknownFlagError); ok { //nolint:errorlint
-				// this means f.ParseErrorsAllowlist.UnknownFlagsHandling is set to UnknownFlagsHandlingPassUnknownToArgs
-				f.args = append(f.args, errUnknownFlag.UnknownFlags)
-				err = nil
-			} else {
-				return
-			}
+			return
 		}
 	}
 	return
 }
 
+// parseArgs parses the arguments, calling fn for every flag, after splitting
+// them into tokens.
+func (f *FlagSet) parseArgs(args []string, fn parseFunc) error {
+	tokens, tokenErr := f.tokenize(args)
+	terminated := false
+	for _, t := range tokens {
+		switch t.Kind {
+		case PositionalToken:
+			f.args = append(f.args, t.Value)
+			if f.returnInOrder && f.interspersed && !terminated {
+				if err := fn(nil, t.Value); err != nil {
+					return f.fail(err)
+				}
+			}
+		case TerminatorToken:
+			f.argsLenAtDash = len(f.args)
+			terminated = true
+		case UnknownFlagToken:
+			f.recordUnknownFlag(t)
+			if t.passed != "" {
+				// f.ParseErrorsAllowlist.UnknownFlagsHandling is set to PassUnknownFlagToArgs
+				if f.passedArgs == nil {
+					f.passedArgs = make(map[int]bool)
+				}
+				f.passedArgs[len(f.args)] = true
+				f.args = append(f.args, t.passed)
+			}
+		default:
+			if err := f.parseFlagToken(t, fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	if tokenErr == ErrHelp { //nolint:errorlint // not using errors.Is for compatibility with go1.12
+		f.usage()
+		return tokenErr
+	}
+	if tokenErr != nil {
+		return f.failUnlessAllowed(tokenErr)
+	}
+	return nil
+}
+
+// parseFlagToken calls fn for the known flag read as the token t.
+func (f *FlagSet) parseFlagToken(t Token, fn parseFunc) error {
+	flag := t.Flag
+	if t.Kind == ShortFlagToken && flag.ShorthandDeprecated != "" {
+		_, _ = fmt.Fprintln(f.Output(), f.translator.translate(MsgShorthandDeprecated, map[string]string{
+			"shorthand": flag.Shorthand,
+			"message":   flag.ShorthandDeprecated,
+		}))
+	}
+
+	if err := f.checkRepeated(t); err != nil {
+		return f.fail(err)
+	}
+	if err := fn(flag, t.Value); err != nil {
+		return f.failUnlessAllowed(err)
+	}
+	f.recordOccurrence(t)
+	return nil
+}
+
 // Parse parses flag definitions from the argument list, which should not
 // include the command name.  Must be called after all flags in the FlagSet
 // are defined and before flags are accessed by the program.
@@ -1289,58 +1544,71 @@ func (f *FlagSet) Parse(arguments []string) error {
 	f.parsed = true
 
 	f.args = make([]string, 0, len(arguments))
+	f.occurrences = nil
+	f.unknownFlags = nil
+	f.passedArgs = nil
 
-	if len(arguments) == 0 {
+	if len(arguments) == 0 && len(f.positionals) == 0 {
 		return nil
 	}
 
 	set := func(flag *Flag, value string) error {
+		if flag == nil {
+			// a non-flag argument with ReturnInOrder
+			return nil
+		}
 		return f.Set(flag.Name, value)
 	}
 
 	err := f.parseArgs(arguments, set)
+	if err == nil {
+		err = f.bindPositionals()
+	}
 	if err != nil {
-		switch f.errorHandling {
-		case ContinueOnError:
-			return err
-		case ExitOnError:
-			if err == ErrHelp { //nolint:errorlint // not using errors.Is for compatibility with go1.12
-				os.Exit(0)
-			}
-			_, _ = fmt.Fprintln(f.Output(), err)
-			os.Exit(2)
-		case PanicOnError:
-			panic(err)
-		}
+		return f.handleError(err)
 	}
 	return nil
 }
 
+// handleError reports a parsing error according to the error handling
+// property of the flag set.
+func (f *FlagSet) handleError(err error) error {
+	switch f.errorHandling {
+	case ContinueOnError:
+		return err
+	case ExitOnError:
+		if err == ErrHelp { //nolint:errorlint // not using errors.Is for compatibility with go1.12
+			os.Exit(0)
+		}
+		_, _ = fmt.Fprintln(f.Output(), err)
+		os.Exit(2)
+	case PanicOnError:
+		panic(err)
+	}
+	return err
+}
+
 type parseFunc func(flag *Flag, value string) error
 
 // ParseAll parses flag definitions from the argument list, which should not
 // include the command name. The arguments for fn are flag and value. Must be
 // called after all flags in the FlagSet are defined and before flags are
 // accessed by the program. The return value will be ErrHelp if -help was set
-// but not defined.
+// but not defined. With the ReturnInOrder ordering fn is also called for the
+// non-flag arguments, with a nil flag.
 func (f *FlagSet) ParseAll(arguments []string, fn func(flag *Flag, value string) error) error {
 	f.parsed = true
 	f.args = make([]string, 0, len(arguments))
+	f.occurrences = nil
+	f.unknownFlags = nil
+	f.passedArgs = nil
 
 	err := f.parseArgs(arguments, fn)
+	if err == nil {
+		err = f.bindPositionals()
+	}
 	if err != nil {
-		switch f.errorHandling {
-		case ContinueOnError:
-			return err
-		case ExitOnError:
-			if err == ErrHelp { //nolint:errorlint // not using errors.Is for compatibility with go1.12
-				os.Exit(0)
-			}
-			_, _ = fmt.Fprintln(f.Output(), err)
-			os.Exit(2)
-		case PanicOnError:
-			panic(err)
-		}
+		return f.handleError(err)
 	}
 	return nil
 }
@@ -45,6 +45,11 @@ func (s *float32SliceValue) Type() string {
 	return "float32Slice"
 }
 
+// clearChanged makes the next Set replace the value instead of adding to it.
+func (s *float32SliceValue) clearChanged() {
+	s.changed = false
+}
+
 func (s *float32SliceValue) String() string {
 	out := make([]string, len(*s.value))
 	for i, d := range *s.value {
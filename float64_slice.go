@@ -43,6 +43,11 @@ func (s *float64SliceValue) Type() string {
 	return "float64Slice"
 }
 
+// clearChanged makes the next Set replace the value instead of adding to it.
+func (s *float64SliceValue) clearChanged() {
+	s.changed = false
+}
+
 func (s *float64SliceValue) String() string {
 	out := make([]string, len(*s.value))
 	for i, d := range *s.value {
@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // go test flags prefixes
@@ -82,7 +83,7 @@ func PFlagFromGoFlag(goflag *goflag.Flag) *Flag { //nolint:revive // ignore "fun
 		DefValue: goflag.Value.String(),
 	}
 	// Ex: if the golang flag was -v, allow both -v and --v to work
-	if len(flag.Name) == 1 {
+	if utf8.RuneCountInString(flag.Name) == 1 {
 		flag.Shorthand = flag.Name
 	}
 	if fv, ok := goflag.Value.(goBoolFlag); ok && fv.IsBoolFlag() {
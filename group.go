@@ -0,0 +1,105 @@
+package pflag
+
+// GroupAlignment defines how the usage columns of grouped flags are aligned.
+type GroupAlignment int
+
+const (
+	// AlignGlobal aligns the usage text of all groups to the same column
+	AlignGlobal GroupAlignment = iota
+	// AlignPerGroup aligns the usage text of each group independently
+	AlignPerGroup
+)
+
+// usageGroup is a named list of flags rendered together in usage output.
+type usageGroup struct {
+	name  string
+	flags []*Flag
+}
+
+// AddGroup registers a usage group. Groups are listed in usage output in the
+// order they were registered, after the flags which belong to no group.
+// Registering a group twice has no effect.
+func (f *FlagSet) AddGroup(name string) {
+	for _, g := range f.groups {
+		if g == name {
+			return
+		}
+	}
+	f.groups = append(f.groups, name)
+}
+
+// addGroupsOf registers the usage groups of newSet which the flags added
+// from it belong to, in the order they were registered in newSet.
+func (f *FlagSet) addGroupsOf(newSet *FlagSet, added []*Flag) {
+	used := make(map[string]bool, len(added))
+	for _, flag := range added {
+		used[flag.Group] = true
+	}
+	for _, group := range newSet.groups {
+		if used[group] {
+			f.AddGroup(group)
+		}
+	}
+}
+
+// SetFlagGroup assigns the named flag to a usage group, registering the group
+// if needed. An empty group removes the flag from its group.
+func (f *FlagSet) SetFlagGroup(name, group string) error {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return f.translateError(&NotExistError{name: name, messageType: flagNotExistMessage})
+	}
+	if group != "" {
+		f.AddGroup(group)
+	}
+	flag.Group = group
+	return nil
+}
+
+// Groups returns a copy of the registered usage groups in registration
+// order.
+func (f *FlagSet) Groups() []string {
+	groups := make([]string, len(f.groups))
+	copy(groups, f.groups)
+	return groups
+}
+
+// SetGroupAlignment sets how usage columns are aligned across groups. The
+// default is AlignGlobal.
+func (f *FlagSet) SetGroupAlignment(alignment GroupAlignment) {
+	f.groupAlignment = alignment
+}
+
+// usageGroups returns the visible flags split by usage group. The flags which
+// belong to no group come first, followed by the registered groups in order
+// and by groups only known from the flags themselves (e.g. added with
+// AddFlagSet) in the order they are first seen. Empty groups are omitted.
+func (f *FlagSet) usageGroups() []usageGroup {
+	groups := []usageGroup{{}}
+	index := map[string]int{"": 0}
+	for _, name := range f.groups {
+		index[name] = len(groups)
+		groups = append(groups, usageGroup{name: name})
+	}
+
+	f.VisitAll(func(flag *Flag) {
+		if flag.Hidden {
+			return
+		}
+		i, ok := index[flag.Group]
+		if !ok {
+			i = len(groups)
+			index[flag.Group] = i
+			groups = append(groups, usageGroup{name: flag.Group})
+		}
+		groups[i].flags = append(groups[i].flags, flag)
+	})
+
+	visible := groups[:0]
+	for _, g := range groups {
+		if len(g.flags) > 0 {
+			visible = append(visible, g)
+		}
+	}
+	return visible
+}
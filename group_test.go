@@ -0,0 +1,116 @@
+package pflag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func setUpGroupedFlagSet() *FlagSet {
+	f := NewFlagSet("test", ContinueOnError)
+	f.BoolP("verbose", "v", false, "verbose output")
+	f.String("host", "localhost", "host to connect to")
+	f.Int("port", 80, "port to connect to")
+	f.String("format", "", "output format")
+	f.Bool("debug-internals", false, "dump internal state")
+	f.AddGroup("Networking")
+	f.AddGroup("Output")
+	_ = f.SetFlagGroup("host", "Networking")
+	_ = f.SetFlagGroup("port", "Networking")
+	_ = f.SetFlagGroup("format", "Output")
+	_ = f.SetFlagGroup("debug-internals", "Advanced")
+	return f
+}
+
+const expectedGroupedOutput = `  -v, --verbose[=true|false]   verbose output
+
+Networking:
+      --host string   host to connect to (default "localhost")
+      --port int      port to connect to (default 80)
+
+Output:
+      --format string   output format
+
+Advanced:
+      --debug-internals[=true|false]   dump internal state
+`
+
+const expectedGloballyAlignedGroupedOutput = `  -v, --verbose[=true|false]           verbose output
+
+Networking:
+      --host string                    host to connect to (default "localhost")
+      --port int                       port to connect to (default 80)
+
+Output:
+      --format string                  output format
+
+Advanced:
+      --debug-internals[=true|false]   dump internal state
+`
+
+func TestGroupedUsage(t *testing.T) {
+	f := setUpGroupedFlagSet()
+	f.SetGroupAlignment(AlignPerGroup)
+	if res := f.FlagUsages(); res != expectedGroupedOutput {
+		t.Errorf("Expected \n%s \nActual \n%s", expectedGroupedOutput, res)
+	}
+
+	f.SetGroupAlignment(AlignGlobal)
+	if res := f.FlagUsages(); res != expectedGloballyAlignedGroupedOutput {
+		t.Errorf("Expected \n%s \nActual \n%s", expectedGloballyAlignedGroupedOutput, res)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	f := setUpGroupedFlagSet()
+	expected := []string{"Networking", "Output", "Advanced"}
+	if !reflect.DeepEqual(f.Groups(), expected) {
+		t.Errorf("expected groups %v, got %v", expected, f.Groups())
+	}
+
+	groups := f.Groups()
+	groups[0] = "Changed"
+	if f.Groups()[0] != "Networking" {
+		t.Error("expected the groups returned not to be shared with the flag set")
+	}
+
+	if err := f.SetFlagGroup("missing", "Output"); err == nil {
+		t.Error("expected an error when grouping an undefined flag")
+	}
+
+	if err := f.SetFlagGroup("format", ""); err != nil {
+		t.Fatal(err)
+	}
+	if f.Lookup("format").Group != "" {
+		t.Error("expected format to have no group")
+	}
+	for _, g := range f.usageGroups() {
+		if g.name == "Output" {
+			t.Error("expected the empty Output group to be omitted")
+		}
+	}
+}
+
+func TestAddFlagSetGroups(t *testing.T) {
+	lib := NewFlagSet("lib", ContinueOnError)
+	lib.String("db-host", "", "database host")
+	_ = lib.SetFlagGroup("db-host", "Database")
+	lib.Bool("verbose", false, "verbose output")
+	_ = lib.SetFlagGroup("verbose", "Output")
+
+	f := NewFlagSet("app", ContinueOnError)
+	f.AddGroup("General")
+	f.Bool("verbose", false, "verbose output")
+	f.AddFlagSet(lib)
+
+	expected := []string{"General", "Database"}
+	if !reflect.DeepEqual(f.Groups(), expected) {
+		t.Errorf("expected groups %v, got %v", expected, f.Groups())
+	}
+
+	f = NewFlagSet("app", ContinueOnError)
+	f.Bool("verbose", false, "verbose output")
+	_ = f.TryAddFlagSet(lib)
+	if !reflect.DeepEqual(f.Groups(), []string{"Database"}) {
+		t.Errorf("expected only the groups of the added flags, got %v", f.Groups())
+	}
+}
@@ -45,6 +45,11 @@ func (s *int32SliceValue) Type() string {
 	return "int32Slice"
 }
 
+// clearChanged makes the next Set replace the value instead of adding to it.
+func (s *int32SliceValue) clearChanged() {
+	s.changed = false
+}
+
 func (s *int32SliceValue) String() string {
 	out := make([]string, len(*s.value))
 	for i, d := range *s.value {
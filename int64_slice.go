@@ -43,6 +43,11 @@ func (s *int64SliceValue) Type() string {
 	return "int64Slice"
 }
 
+// clearChanged makes the next Set replace the value instead of adding to it.
+func (s *int64SliceValue) clearChanged() {
+	s.changed = false
+}
+
 func (s *int64SliceValue) String() string {
 	out := make([]string, len(*s.value))
 	for i, d := range *s.value {
@@ -43,6 +43,11 @@ func (s *intSliceValue) Type() string {
 	return "intSlice"
 }
 
+// clearChanged makes the next Set replace the value instead of adding to it.
+func (s *intSliceValue) clearChanged() {
+	s.changed = false
+}
+
 func (s *intSliceValue) String() string {
 	out := make([]string, len(*s.value))
 	for i, d := range *s.value {
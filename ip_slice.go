@@ -58,6 +58,11 @@ func (s *ipSliceValue) Type() string {
 	return "ipSlice"
 }
 
+// clearChanged makes the next Set replace the value instead of adding to it.
+func (s *ipSliceValue) clearChanged() {
+	s.changed = false
+}
+
 // String defines a "native" format for this net.IP slice flag value.
 func (s *ipSliceValue) String() string {
 	ipStrSlice := make([]string, len(*s.value))
@@ -58,6 +58,11 @@ func (s *ipNetSliceValue) Type() string {
 	return "ipNetSlice"
 }
 
+// clearChanged makes the next Set replace the value instead of adding to it.
+func (s *ipNetSliceValue) clearChanged() {
+	s.changed = false
+}
+
 // String defines a "native" format for this net.IPNet slice flag value.
 func (s *ipNetSliceValue) String() string {
 	ipNetStrSlice := make([]string, len(*s.value))
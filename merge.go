@@ -0,0 +1,249 @@
+package pflag
+
+// MergePolicy decides how Merge resolves a flag whose name or shorthand is
+// already used in the FlagSet.
+type MergePolicy int
+
+const (
+	// MergeKeep keeps the existing flag and does not add the conflicting
+	// one, as AddFlagSet does for names.
+	MergeKeep MergePolicy = iota
+	// MergeOverride replaces the existing flag with the same name by the new
+	// one, and takes the shorthand away from the existing flag using it by
+	// replacing that flag with a copy without shorthand.
+	MergeOverride
+	// MergeRename adds the new flag under its name with a prefix, e.g.
+	// "plugin-config". A shorthand cannot be prefixed, so a conflicting
+	// shorthand is dropped. If the prefixed name is also used, the existing
+	// flag is kept.
+	MergeRename
+	// MergeDropShorthand adds the new flag without its conflicting
+	// shorthand. On a name conflict, the existing flag is kept.
+	MergeDropShorthand
+	// MergeError makes Merge fail with a *FlagRedefinedError or a
+	// *ShorthandConflictError, leaving the FlagSet unchanged.
+	MergeError
+)
+
+// A MergeConflict describes a flag which conflicted with the FlagSet it was
+// merged into, and how the conflict was resolved.
+type MergeConflict struct {
+	Flag           *Flag       // the flag of the merged set
+	Existing       *Flag       // the flag with the same name; nil if only the shorthand conflicts
+	ShorthandOwner *Flag       // the flag using the same shorthand; nil if only the name conflicts
+	Action         MergePolicy // how the conflict was resolved; MergeKeep if the flag was not added
+	Added          *Flag       // the flag added: Flag itself, or a renamed copy or a copy without shorthand; nil if none
+}
+
+// A MergeReport tells what Merge did.
+type MergeReport struct {
+	Added     []*Flag         // the flags added, including the renamed copies and copies without shorthand
+	Conflicts []MergeConflict // the flags which conflicted, in the order they were merged
+}
+
+// Merge adds the flags of newSet to f, resolving the flags whose name or
+// shorthand is already used in f with the given policy. prefix is used by
+// MergeRename, and should usually end with a separator, e.g. "plugin-".
+// Copies of the flags of newSet are added when they are renamed or lose
+// their shorthand; they share their Value with the original flags. Flags
+// already present in f, as when the same flags are merged again, are
+// skipped. The usage groups of the flags added are registered in f after
+// its own.
+//
+// Every conflict is resolved before f is changed: if a flag cannot be
+// added, e.g. because of an invalid shorthand or, with MergeError, of a
+// conflict, Merge returns the error with an empty report and f is left
+// unchanged. The flags of f are never modified, as they may be shared with
+// other FlagSets: they are replaced with copies instead.
+func (f *FlagSet) Merge(newSet *FlagSet, policy MergePolicy, prefix string) (*MergeReport, error) {
+	if newSet == nil {
+		return &MergeReport{}, nil
+	}
+
+	plan := &mergePlan{
+		f:          f,
+		formal:     make(map[NormalizedName]*Flag, len(f.formal)),
+		shorthands: make(map[string]*Flag, len(f.shorthands)),
+	}
+	for name, flag := range f.formal {
+		plan.formal[name] = flag
+	}
+	for shorthand, flag := range f.shorthands {
+		plan.shorthands[shorthand] = flag
+	}
+
+	var err error
+	newSet.VisitAll(func(flag *Flag) {
+		if err == nil && f.Lookup(flag.Name) != flag {
+			err = plan.merge(flag, policy, prefix)
+		}
+	})
+	if err != nil {
+		return &MergeReport{}, err
+	}
+
+	report := &MergeReport{}
+	for _, step := range plan.steps {
+		if step.removed != nil {
+			f.removeFlag(step.removed)
+		}
+		if step.owner != nil {
+			f.replaceFlag(step.owner, step.ownerCopy)
+		}
+		if step.added != nil {
+			_ = f.TryAddFlag(step.added) // cannot fail, checked by the plan
+			report.Added = append(report.Added, step.added)
+		}
+		if step.conflict.Existing != nil || step.conflict.ShorthandOwner != nil {
+			report.Conflicts = append(report.Conflicts, step.conflict)
+		}
+	}
+	f.addGroupsOf(newSet, report.Added)
+	return report, nil
+}
+
+// A mergePlan holds the changes Merge makes to a FlagSet, and the flags and
+// shorthands the FlagSet will have once they are made.
+type mergePlan struct {
+	f          *FlagSet
+	formal     map[NormalizedName]*Flag
+	shorthands map[string]*Flag
+	steps      []mergeStep
+}
+
+// A mergeStep is the change Merge makes for one flag.
+type mergeStep struct {
+	conflict  MergeConflict
+	removed   *Flag // the existing flag replaced with MergeOverride
+	owner     *Flag // the flag whose shorthand is taken over with MergeOverride
+	ownerCopy *Flag // the copy of owner without shorthand which replaces it
+	added     *Flag
+}
+
+// merge plans how to merge flag with the policy, or returns why it cannot
+// be merged.
+func (p *mergePlan) merge(flag *Flag, policy MergePolicy, prefix string) error {
+	f := p.f
+	existing := p.formal[f.normalizeFlagName(flag.Name)]
+	var owner *Flag
+	if flag.Shorthand != "" {
+		owner = p.shorthands[flag.Shorthand]
+	}
+	if policy == MergeError {
+		if existing != nil {
+			return f.translateError(&FlagRedefinedError{flagSetName: f.name, flag: flag, existing: existing})
+		}
+		if owner != nil {
+			return f.translateError(&ShorthandConflictError{flagSetName: f.name, flag: flag, existing: owner})
+		}
+	}
+
+	step := mergeStep{conflict: MergeConflict{
+		Flag:           flag,
+		Existing:       existing,
+		ShorthandOwner: owner,
+		Action:         policy,
+	}}
+	step.added = p.resolveConflict(&step, flag, existing, owner, policy, prefix)
+	if step.added != nil {
+		name := f.normalizeFlagName(step.added.Name)
+		if err := f.checkNewFlag(step.added, name, p.formal, p.shorthands); err != nil {
+			return err
+		}
+		p.formal[name] = step.added
+		if step.added.Shorthand != "" {
+			p.shorthands[step.added.Shorthand] = step.added
+		}
+	}
+	step.conflict.Added = step.added
+	if step.added == nil {
+		step.conflict.Action = MergeKeep
+	}
+	p.steps = append(p.steps, step)
+	return nil
+}
+
+// resolveConflict applies the policy to a flag being merged and returns the
+// flag to add, or nil if none is.
+func (p *mergePlan) resolveConflict(step *mergeStep, flag, existing, owner *Flag, policy MergePolicy, prefix string) *Flag {
+	added := flag
+	if existing != nil {
+		switch policy {
+		case MergeOverride:
+			step.removed = existing
+			delete(p.formal, p.f.normalizeFlagName(existing.Name))
+			if existing.Shorthand != "" && p.shorthands[existing.Shorthand] == existing {
+				delete(p.shorthands, existing.Shorthand)
+			}
+			if owner == existing {
+				owner = nil
+			}
+		case MergeRename:
+			renamed := *flag
+			renamed.Name = prefix + flag.Name
+			if p.formal[p.f.normalizeFlagName(renamed.Name)] != nil {
+				return nil
+			}
+			added = &renamed
+		default:
+			return nil
+		}
+	}
+	if owner != nil {
+		switch policy {
+		case MergeOverride:
+			ownerCopy := *owner
+			ownerCopy.Shorthand = ""
+			step.owner, step.ownerCopy = owner, &ownerCopy
+			delete(p.shorthands, owner.Shorthand)
+			p.formal[p.f.normalizeFlagName(owner.Name)] = &ownerCopy
+		case MergeRename, MergeDropShorthand:
+			copied := *added
+			copied.Shorthand = ""
+			added = &copied
+		default:
+			return nil
+		}
+	}
+	return added
+}
+
+// replaceFlag puts flag in the place of old in the FlagSet, keeping its
+// position and whether and how it was given on the command line.
+func (f *FlagSet) replaceFlag(old, flag *Flag) {
+	name := f.normalizeFlagName(old.Name)
+	f.formal[name] = flag
+	replaceInFlags(f.orderedFormal, old, flag)
+	f.sortedFormal = nil
+	if old.Shorthand != "" && f.shorthands[old.Shorthand] == old {
+		delete(f.shorthands, old.Shorthand)
+		f.updateMaxShorthandLen()
+	}
+	if flag.Shorthand != "" {
+		f.shorthands[flag.Shorthand] = flag
+	}
+	if _, changed := f.actual[name]; changed {
+		f.actual[name] = flag
+		replaceInFlags(f.orderedActual, old, flag)
+		f.sortedActual = nil
+	}
+	if occurrences, given := f.occurrences[name]; given {
+		replaced := make([]Occurrence, len(occurrences))
+		for i, o := range occurrences {
+			if o.Flag == old {
+				o.Flag = flag
+			}
+			replaced[i] = o
+		}
+		f.occurrences[name] = replaced
+	}
+}
+
+// replaceInFlags replaces old with flag in flags.
+func replaceInFlags(flags []*Flag, old, flag *Flag) {
+	for i, fl := range flags {
+		if fl == old {
+			flags[i] = flag
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package pflag
+
+import (
+	"reflect"
+	"testing"
+)
+
+var mergePolicyNames = map[MergePolicy]string{
+	MergeKeep:          "keep",
+	MergeOverride:      "override",
+	MergeRename:        "rename",
+	MergeDropShorthand: "drop shorthand",
+	MergeError:         "error",
+}
+
+// describeMergeReport describes the added flags and the conflicts of a
+// report as "name" and "name: action -> added" strings.
+func describeMergeReport(report *MergeReport) (added, conflicts []string) {
+	for _, flag := range report.Added {
+		added = append(added, flag.Name)
+	}
+	for _, c := range report.Conflicts {
+		desc := c.Flag.Name + ": " + mergePolicyNames[c.Action]
+		if c.Added != nil {
+			desc += " -> " + c.Added.Name
+		}
+		conflicts = append(conflicts, desc)
+	}
+	return added, conflicts
+}
+
+func TestMerge(t *testing.T) {
+	cases := []struct {
+		desc      string
+		policy    MergePolicy
+		prefix    string
+		extra     func(f, plugin *FlagSet) // more flags, if any
+		added     []string
+		conflicts []string
+		err       string
+		check     func(t *testing.T, f, plugin *FlagSet)
+	}{
+		{
+			desc:      "keep",
+			policy:    MergeKeep,
+			added:     []string{"retries"},
+			conflicts: []string{"config: keep", "version: keep"},
+			check: func(t *testing.T, f, plugin *FlagSet) {
+				if f.Lookup("config").Usage != "host config" || f.Lookup("version") != nil {
+					t.Error("expected the existing flags to be kept")
+				}
+			},
+		},
+		{
+			desc:      "override",
+			policy:    MergeOverride,
+			added:     []string{"config", "retries", "version"},
+			conflicts: []string{"config: override -> config", "version: override -> version"},
+			check: func(t *testing.T, f, plugin *FlagSet) {
+				if f.Lookup("config") != plugin.Lookup("config") || f.ShorthandLookup("c") != nil {
+					t.Error("expected config to be replaced, with its shorthand")
+				}
+				if f.ShorthandLookup("v") != plugin.Lookup("version") || f.Lookup("verbose").Shorthand != "" {
+					t.Error("expected the shorthand v to be taken over by version")
+				}
+				if err := f.Parse([]string{"--config=x", "-v", "--verbose"}); err != nil {
+					t.Fatal(err)
+				}
+				if config, _ := plugin.GetString("config"); config != "x" {
+					t.Errorf("expected the plugin's config to be set, got %q", config)
+				}
+			},
+		},
+		{
+			desc:      "rename",
+			policy:    MergeRename,
+			prefix:    "plugin-",
+			added:     []string{"plugin-config", "retries", "version"},
+			conflicts: []string{"config: rename -> plugin-config", "version: rename -> version"},
+			check: func(t *testing.T, f, plugin *FlagSet) {
+				if f.Lookup("plugin-config").Value != plugin.Lookup("config").Value || plugin.Lookup("config").Name != "config" {
+					t.Error("expected a renamed copy of config sharing its value")
+				}
+				if f.Lookup("version").Shorthand != "" || plugin.Lookup("version").Shorthand != "v" {
+					t.Error("expected a copy of version without its shorthand")
+				}
+			},
+		},
+		{
+			desc:      "rename to a used name",
+			policy:    MergeRename,
+			prefix:    "plugin-",
+			extra:     func(f, plugin *FlagSet) { f.String("plugin-config", "", "plugin config") },
+			added:     []string{"retries", "version"},
+			conflicts: []string{"config: keep", "version: rename -> version"},
+		},
+		{
+			desc:      "drop shorthand",
+			policy:    MergeDropShorthand,
+			added:     []string{"retries", "version"},
+			conflicts: []string{"config: keep", "version: drop shorthand -> version"},
+			check: func(t *testing.T, f, plugin *FlagSet) {
+				if f.Lookup("version").Shorthand != "" || f.ShorthandLookup("v") != f.Lookup("verbose") {
+					t.Error("expected version to be added without its shorthand")
+				}
+			},
+		},
+		{
+			desc:   "error",
+			policy: MergeError,
+			err:    "host flag redefined: config",
+		},
+		{
+			desc:   "invalid shorthand",
+			policy: MergeOverride,
+			extra: func(f, plugin *FlagSet) {
+				plugin.SetMultiCharShorthands(true)
+				plugin.BoolP("zz", "zz", false, "zz")
+			},
+			err: `"zz" shorthand is more than one ASCII character`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f := NewFlagSet("host", ContinueOnError)
+			f.StringP("config", "c", "host.yaml", "host config")
+			f.BoolP("verbose", "v", false, "verbose")
+			plugin := NewFlagSet("plugin", ContinueOnError)
+			plugin.String("config", "plugin.yaml", "plugin config")
+			plugin.BoolP("version", "v", false, "version")
+			plugin.IntP("retries", "r", 3, "retries")
+			if tc.extra != nil {
+				tc.extra(f, plugin)
+			}
+			formal := map[NormalizedName]Flag{}
+			f.VisitAll(func(flag *Flag) { formal[NormalizedName(flag.Name)] = *flag })
+
+			report, err := f.Merge(plugin, tc.policy, tc.prefix)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("expected the error %q, got %v", tc.err, err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			added, conflicts := describeMergeReport(report)
+			if !reflect.DeepEqual(added, tc.added) {
+				t.Errorf("expected the flags %q to be added, got %q", tc.added, added)
+			}
+			if !reflect.DeepEqual(conflicts, tc.conflicts) {
+				t.Errorf("expected the conflicts %q, got %q", tc.conflicts, conflicts)
+			}
+			if tc.err != "" {
+				after := map[NormalizedName]Flag{}
+				f.VisitAll(func(flag *Flag) { after[NormalizedName(flag.Name)] = *flag })
+				if !reflect.DeepEqual(after, formal) || len(f.shorthands) != 2 {
+					t.Error("expected the flag set to be unchanged after an error")
+				}
+			}
+			if tc.check != nil {
+				tc.check(t, f, plugin)
+			}
+		})
+	}
+}
+
+func TestMergeSharedFlags(t *testing.T) {
+	lib := NewFlagSet("lib", ContinueOnError)
+	lib.BoolP("verbose", "v", false, "verbose")
+	f := NewFlagSet("host", ContinueOnError)
+	f.AddFlagSet(lib)
+	if err := f.Parse([]string{"-v"}); err != nil {
+		t.Fatal(err)
+	}
+
+	plugin := NewFlagSet("plugin", ContinueOnError)
+	plugin.BoolP("version", "v", false, "version")
+	if _, err := f.Merge(plugin, MergeOverride, ""); err != nil {
+		t.Fatal(err)
+	}
+	if lib.Lookup("verbose").Shorthand != "v" || lib.ShorthandLookup("v") != lib.Lookup("verbose") {
+		t.Error("expected the flag shared with lib to keep its shorthand")
+	}
+	verbose := f.Lookup("verbose")
+	if verbose == lib.Lookup("verbose") || verbose.Shorthand != "" || verbose.Value != lib.Lookup("verbose").Value {
+		t.Error("expected a copy of verbose without shorthand, sharing its value")
+	}
+	if !f.Changed("verbose") || f.NFlag() != 1 || f.Occurrences("verbose")[0].Flag != verbose {
+		t.Error("expected the copy to keep being given on the command line")
+	}
+}
+
+func TestMergeAgain(t *testing.T) {
+	f := NewFlagSet("host", ContinueOnError)
+	f.AddGroup("General")
+	plugin := NewFlagSet("plugin", ContinueOnError)
+	plugin.Int("retries", 3, "retries")
+	_ = plugin.SetFlagGroup("retries", "Plugin")
+	plugin.AddGroup("Unused")
+
+	if _, err := f.Merge(plugin, MergeError, ""); err != nil {
+		t.Fatal(err)
+	}
+	report, err := f.Merge(plugin, MergeError, "")
+	if err != nil || len(report.Added) != 0 || len(report.Conflicts) != 0 {
+		t.Errorf("expected merging the same flags again to be a no-op, got %+v, %v", report, err)
+	}
+	if !reflect.DeepEqual(f.Groups(), []string{"General", "Plugin"}) {
+		t.Errorf("expected only the groups of the added flags, got %q", f.Groups())
+	}
+}
@@ -0,0 +1,222 @@
+package pflag
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// MessageID identifies a user-visible message printed or returned by pflag.
+type MessageID string
+
+// The messages pflag produces, with the parameters each one receives.
+const (
+	// MsgFlagNotExist is the error for accessing an undefined flag. Params: name.
+	MsgFlagNotExist MessageID = "flag_not_exist"
+	// MsgNoSuchFlag is the error for setting an undefined flag. Params: name.
+	MsgNoSuchFlag MessageID = "no_such_flag"
+	// MsgUnknownFlag is the error for an unknown long flag on the command line. Params: name.
+	MsgUnknownFlag MessageID = "unknown_flag"
+	// MsgUnknownShorthandFlag is the error for an unknown shorthand on the
+	// command line. Params: name, shorthands.
+	MsgUnknownShorthandFlag MessageID = "unknown_shorthand_flag"
+	// MsgFlagNeedsArgument is the error for a long flag given without its
+	// required value. Params: name.
+	MsgFlagNeedsArgument MessageID = "flag_needs_argument"
+	// MsgShorthandNeedsArgument is the error for a shorthand given without
+	// its required value. Params: name, shorthands.
+	MsgShorthandNeedsArgument MessageID = "shorthand_needs_argument"
+	// MsgInvalidArgument is the error for a value rejected by a flag.
+	// Params: value, flag (e.g. "-p, --port"), cause.
+	MsgInvalidArgument MessageID = "invalid_argument"
+	// MsgMissingArgument is the error for a missing required positional
+	// argument. Params: name.
+	MsgMissingArgument MessageID = "missing_argument"
+	// MsgTooManyArguments is the error for more non-flag arguments than
+	// positional arguments. Params: max, actual.
+	MsgTooManyArguments MessageID = "too_many_arguments"
+	// MsgInvalidPositional is the error for a value rejected by a positional
+	// argument. Params: value, name, cause.
+	MsgInvalidPositional MessageID = "invalid_positional"
+	// MsgUnknownCommand is the error for an unknown command given to a
+	// Router. Params: name.
+	MsgUnknownCommand MessageID = "unknown_command"
+	// MsgDidYouMean lists the commands close to an unknown one. Params:
+	// suggestions, separated by "\n\t".
+	MsgDidYouMean MessageID = "did_you_mean"
+	// MsgRepeatedFlag is the error for a single-valued flag given more than
+	// once. Params: name, first, second (e.g. "--port 80").
+	MsgRepeatedFlag MessageID = "repeated_flag"
+	// MsgRepeatedFlagWarning is the warning printed when a single-valued
+	// flag is given more than once. Params: name, first, second.
+	MsgRepeatedFlagWarning MessageID = "repeated_flag_warning"
+	// MsgAmbiguousValue is the error for an argument which could be either
+	// the optional value of a flag or a non-flag argument. Params: name, value.
+	MsgAmbiguousValue MessageID = "ambiguous_value"
+	// MsgFlagRedefined is the error for defining a flag twice. Params:
+	// flagset, name.
+	MsgFlagRedefined MessageID = "flag_redefined"
+	// MsgShorthandConflict is the error for defining a shorthand twice.
+	// Params: flagset, shorthand, name, existing (the flag using it).
+	MsgShorthandConflict MessageID = "shorthand_conflict"
+	// MsgInvalidShorthand is the error for a shorthand of more than one
+	// character. Params: shorthand, name.
+	MsgInvalidShorthand MessageID = "invalid_shorthand"
+	// MsgBadFlagSyntax is the error for a malformed flag. Params: flag.
+	MsgBadFlagSyntax MessageID = "bad_flag_syntax"
+	// MsgFlagDeprecated is the warning printed when a deprecated flag is
+	// used. Params: name, message.
+	MsgFlagDeprecated MessageID = "flag_deprecated"
+	// MsgShorthandDeprecated is the warning printed when a deprecated
+	// shorthand is used. Params: shorthand, message.
+	MsgShorthandDeprecated MessageID = "shorthand_deprecated"
+	// MsgUsageDefault is the usage suffix showing a default value. Params: value.
+	MsgUsageDefault MessageID = "usage_default"
+	// MsgUsageDeprecated is the usage suffix of a deprecated flag. Params: message.
+	MsgUsageDeprecated MessageID = "usage_deprecated"
+	// MsgUsageArguments is the header of the positional arguments printed
+	// by the default usage function.
+	MsgUsageArguments MessageID = "usage_arguments"
+	// MsgUsageCommands is the header of the commands printed by a Router.
+	MsgUsageCommands MessageID = "usage_commands"
+	// MsgUsageFlags is the header of the flags printed by a Router.
+	MsgUsageFlags MessageID = "usage_flags"
+	// MsgUsageOf is the header printed by the default usage function. Params: name.
+	MsgUsageOf MessageID = "usage_of"
+)
+
+// Message is a user-visible message with the parameters it is built from.
+type Message struct {
+	ID     MessageID
+	Params map[string]string
+}
+
+// String returns the default English text of the message.
+func (m Message) String() string {
+	p := m.Params
+	switch m.ID {
+	case MsgFlagNotExist:
+		return fmt.Sprintf("flag %q does not exist", p["name"])
+	case MsgNoSuchFlag:
+		return fmt.Sprintf("no such flag -%v", p["name"])
+	case MsgUnknownFlag:
+		return fmt.Sprintf("unknown flag: --%s", p["name"])
+	case MsgUnknownShorthandFlag:
+		return fmt.Sprintf("unknown shorthand flag: %s in -%s", quoteShorthand(p["name"]), p["shorthands"])
+	case MsgFlagNeedsArgument:
+		return fmt.Sprintf("flag needs an argument: --%s", p["name"])
+	case MsgShorthandNeedsArgument:
+		return fmt.Sprintf("flag needs an argument: %s in -%s", quoteShorthand(p["name"]), p["shorthands"])
+	case MsgInvalidArgument:
+		return fmt.Sprintf("invalid argument %q for %q flag: %v", p["value"], p["flag"], p["cause"])
+	case MsgMissingArgument:
+		return fmt.Sprintf("missing required argument: <%s>", p["name"])
+	case MsgTooManyArguments:
+		return fmt.Sprintf("too many arguments: expected at most %s, got %s", p["max"], p["actual"])
+	case MsgInvalidPositional:
+		return fmt.Sprintf("invalid argument %q for <%s>: %v", p["value"], p["name"], p["cause"])
+	case MsgUnknownCommand:
+		return fmt.Sprintf("unknown command %q", p["name"])
+	case MsgDidYouMean:
+		return fmt.Sprintf("Did you mean this?\n\t%s", p["suggestions"])
+	case MsgRepeatedFlag:
+		return fmt.Sprintf("flag --%s given more than once: %q and %q", p["name"], p["first"], p["second"])
+	case MsgRepeatedFlagWarning:
+		return fmt.Sprintf("Flag --%s given more than once, %q overrides %q", p["name"], p["second"], p["first"])
+	case MsgAmbiguousValue:
+		return fmt.Sprintf("ambiguous argument %q after flag --%s: use --%s=%s to set the flag to it", p["value"], p["name"], p["name"], p["value"])
+	case MsgFlagRedefined:
+		return fmt.Sprintf("%s flag redefined: %s", p["flagset"], p["name"])
+	case MsgShorthandConflict:
+		return fmt.Sprintf("unable to redefine %s shorthand in %q flagset: it's already used for %q flag", quoteShorthand(p["shorthand"]), p["flagset"], p["existing"])
+	case MsgInvalidShorthand:
+		return fmt.Sprintf("%q shorthand is more than one ASCII character", p["shorthand"])
+	case MsgBadFlagSyntax:
+		return fmt.Sprintf("bad flag syntax: %s", p["flag"])
+	case MsgFlagDeprecated:
+		return fmt.Sprintf("Flag --%s has been deprecated, %s", p["name"], p["message"])
+	case MsgShorthandDeprecated:
+		return fmt.Sprintf("Flag shorthand -%s has been deprecated, %s", p["shorthand"], p["message"])
+	case MsgUsageDefault:
+		return fmt.Sprintf("(default %s)", p["value"])
+	case MsgUsageDeprecated:
+		return fmt.Sprintf("(DEPRECATED: %s)", p["message"])
+	case MsgUsageArguments:
+		return "Arguments:"
+	case MsgUsageCommands:
+		return "Commands:"
+	case MsgUsageFlags:
+		return "Flags:"
+	case MsgUsageOf:
+		return fmt.Sprintf("Usage of %s:", p["name"])
+	}
+	return string(m.ID)
+}
+
+// quoteShorthand quotes a shorthand as it is reported in messages: as a
+// character, e.g. 'v', or as a string if it is more than one character.
+func quoteShorthand(s string) string {
+	if r, size := utf8.DecodeRuneInString(s); size == len(s) {
+		return fmt.Sprintf("%q", r)
+	}
+	return fmt.Sprintf("%q", s)
+}
+
+// Translator returns the localized text of a message. Returning an empty
+// string falls back to the default English text.
+type Translator func(msg Message) string
+
+// translate returns the text of the message using t, falling back to the
+// default English text if t is nil or has no translation.
+func (t Translator) translate(id MessageID, params map[string]string) string {
+	msg := Message{ID: id, Params: params}
+	if t != nil {
+		if s := t(msg); s != "" {
+			return s
+		}
+	}
+	return msg.String()
+}
+
+// SetTranslator sets the translator used for the errors, warnings and usage
+// text produced by the FlagSet. A nil translator restores the default
+// English messages.
+func (f *FlagSet) SetTranslator(t Translator) {
+	f.translator = t
+}
+
+// GetTranslator returns the translator set with SetTranslator, or nil.
+func (f *FlagSet) GetTranslator() Translator {
+	return f.translator
+}
+
+// translateError attaches the FlagSet's translator to the errors defined
+// by pflag, so that their Error method returns localized text.
+func (f *FlagSet) translateError(err error) error {
+	switch e := err.(type) { //nolint:errorlint // only errors created by pflag itself are translated
+	case *NotExistError:
+		e.translator = f.translator
+	case *ValueRequiredError:
+		e.translator = f.translator
+	case *InvalidValueError:
+		e.translator = f.translator
+	case *InvalidSyntaxError:
+		e.translator = f.translator
+	case *ArityError:
+		e.translator = f.translator
+	case *InvalidPositionalError:
+		e.translator = f.translator
+	case *UnknownCommandError:
+		e.translator = f.translator
+	case *RepeatedFlagError:
+		e.translator = f.translator
+	case *AmbiguousValueError:
+		e.translator = f.translator
+	case *FlagRedefinedError:
+		e.translator = f.translator
+	case *ShorthandConflictError:
+		e.translator = f.translator
+	case *InvalidShorthandError:
+		e.translator = f.translator
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+package pflag
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var germanMessages = map[MessageID]string{
+	MsgUnknownFlag:       "unbekanntes Flag: --%s",
+	MsgFlagNeedsArgument: "Flag benötigt ein Argument: --%s",
+	MsgUsageDefault:      "(Standard %s)",
+	MsgFlagDeprecated:    "Flag --%s ist veraltet, %s",
+}
+
+func germanTranslator(msg Message) string {
+	format, ok := germanMessages[msg.ID]
+	if !ok {
+		return ""
+	}
+	switch msg.ID {
+	case MsgUnknownFlag, MsgFlagNeedsArgument:
+		return fmt.Sprintf(format, msg.Params["name"])
+	case MsgUsageDefault:
+		return fmt.Sprintf(format, msg.Params["value"])
+	case MsgFlagDeprecated:
+		return fmt.Sprintf(format, msg.Params["name"], msg.Params["message"])
+	}
+	return ""
+}
+
+func TestTranslatedErrors(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	f.String("name", "", "name")
+	f.Int("count", 0, "count")
+	f.SetTranslator(germanTranslator)
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"--unknown"}, "unbekanntes Flag: --unknown"},
+		{[]string{"--name"}, "Flag benötigt ein Argument: --name"},
+		// no translation available, falls back to English
+		{[]string{"-x"}, "unknown shorthand flag: 'x' in -x"},
+		{[]string{"--count=abc"}, `invalid argument "abc" for "--count" flag: must be an integer`},
+	}
+	for _, test := range tests {
+		err := f.Parse(test.args)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("Parse(%v): expected error %q, got %v", test.args, test.expected, err)
+		}
+	}
+}
+
+func TestTranslatedUsageAndWarnings(t *testing.T) {
+	var buf bytes.Buffer
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetOutput(&buf)
+	f.String("name", "world", "who to greet")
+	f.String("old", "", "old flag")
+	_ = f.MarkDeprecated("old", "benutze --name")
+	f.SetTranslator(germanTranslator)
+
+	if usage := f.FlagUsages(); !strings.Contains(usage, `who to greet (Standard "world")`) {
+		t.Errorf("expected translated default in usage, got %q", usage)
+	}
+
+	if err := f.Parse([]string{"--old=x"}); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); out != "Flag --old ist veraltet, benutze --name\n" {
+		t.Errorf("expected translated deprecation warning, got %q", out)
+	}
+}
+
+func TestDefaultMessagesUnchanged(t *testing.T) {
+	tests := []struct {
+		msg      Message
+		expected string
+	}{
+		{Message{ID: MsgFlagNotExist, Params: map[string]string{"name": "x"}}, `flag "x" does not exist`},
+		{Message{ID: MsgShorthandNeedsArgument, Params: map[string]string{"name": "n", "shorthands": "an"}}, `flag needs an argument: 'n' in -an`},
+		{Message{ID: MsgUsageDeprecated, Params: map[string]string{"message": "gone"}}, `(DEPRECATED: gone)`},
+		{Message{ID: MsgUsageOf, Params: map[string]string{"name": "cmd"}}, `Usage of cmd:`},
+	}
+	for _, test := range tests {
+		if s := test.msg.String(); s != test.expected {
+			t.Errorf("expected %q, got %q", test.expected, s)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package pflag
+
+// NamespaceSeparator separates the namespace from the name of the flags
+// added with AddNamespace, as in "db.host".
+const NamespaceSeparator = "."
+
+// AddNamespace adds the flags of set to f under the namespace, so that the
+// flag "host" of set is "--db.host" in f for the namespace "db". The flags of
+// f are copies sharing their Value with the flags of set: when f is parsed,
+// set sees the values and the changed state of its own flags under their
+// own names. The link only goes from f to set: the copies start unchanged in
+// f, and setting or parsing set afterwards changes their value but does not
+// mark them as changed in f. Shorthands are not added, as they are not
+// namespaced. The flags are listed in usage under a group named after the
+// namespace, e.g. "db", or under "db/<group>" for the flags of set in a
+// group. If a namespaced name is already used in f, the error of TryAddFlag
+// is returned and f is unchanged.
+func (f *FlagSet) AddNamespace(namespace string, set *FlagSet) error {
+	var flags []*Flag
+	set.VisitAll(func(flag *Flag) {
+		nsFlag := *flag
+		nsFlag.Name = namespace + NamespaceSeparator + flag.Name
+		nsFlag.Shorthand = ""
+		nsFlag.ShorthandDeprecated = ""
+		nsFlag.Changed = false
+		nsFlag.Group = namespace
+		if flag.Group != "" {
+			nsFlag.Group = namespace + "/" + flag.Group
+		}
+		nsFlag.namespace = namespace
+		nsFlag.origin = flag
+		nsFlag.originSet = set
+		flags = append(flags, &nsFlag)
+	})
+
+	for i, flag := range flags {
+		if err := f.TryAddFlag(flag); err != nil {
+			for _, added := range flags[:i] {
+				f.removeFlag(added)
+			}
+			return err
+		}
+	}
+
+	if len(flags) == 0 {
+		return nil
+	}
+	f.AddGroup(namespace)
+	used := make(map[string]bool, len(set.groups))
+	for _, flag := range set.formal {
+		used[flag.Group] = true
+	}
+	for _, group := range set.groups {
+		if used[group] {
+			f.AddGroup(namespace + "/" + group)
+		}
+	}
+	return nil
+}
+
+// Namespace returns the namespace the flag was added under by AddNamespace
+// and the flag of the namespaced FlagSet it was created from. It returns ""
+// and nil for other flags.
+func (f *Flag) Namespace() (namespace string, origin *Flag) {
+	return f.namespace, f.origin
+}
@@ -0,0 +1,213 @@
+package pflag
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNamespaces(t *testing.T) {
+	cases := []struct {
+		desc    string
+		args    []string
+		host    string
+		port    int
+		changed []string // the changed flags of the application
+	}{
+		{"none", nil, "localhost", 5432, nil},
+		{"inline", []string{"--db.host=db.example.com"}, "db.example.com", 5432, []string{"db.host"}},
+		{"next argument", []string{"--db.port", "1", "-v"}, "localhost", 1, []string{"db.port", "verbose"}},
+		{"both", []string{"--db.port=1", "--db.host", "h"}, "h", 1, []string{"db.host", "db.port"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			db := NewFlagSet("db", ContinueOnError)
+			db.StringP("host", "H", "localhost", "database host")
+			db.Int("port", 5432, "database port")
+			app := NewFlagSet("app", ContinueOnError)
+			app.BoolP("verbose", "v", false, "verbose")
+			if err := app.AddNamespace("db", db); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := app.Parse(tc.args); err != nil {
+				t.Fatal(err)
+			}
+			if host, _ := db.GetString("host"); host != tc.host {
+				t.Errorf("expected the library to read its host %q, got %q", tc.host, host)
+			}
+			if port, _ := db.GetInt("port"); port != tc.port {
+				t.Errorf("expected the library to read its port %d, got %d", tc.port, port)
+			}
+			if host, _ := app.GetString("db.host"); host != tc.host {
+				t.Errorf("expected the application to read the host %q, got %q", tc.host, host)
+			}
+			var changed, libChanged []string
+			app.Visit(func(flag *Flag) { changed = append(changed, flag.Name) })
+			db.Visit(func(flag *Flag) { libChanged = append(libChanged, "db."+flag.Name) })
+			if !reflect.DeepEqual(changed, tc.changed) {
+				t.Errorf("expected the changed flags %q, got %q", tc.changed, changed)
+			}
+			for _, name := range libChanged {
+				if !app.Changed(name) {
+					t.Errorf("expected %s to be changed in both sets", name)
+				}
+			}
+		})
+	}
+}
+
+func TestNamespaceFlags(t *testing.T) {
+	db := NewFlagSet("db", ContinueOnError)
+	db.StringP("host", "H", "localhost", "database host")
+	app := NewFlagSet("app", ContinueOnError)
+	app.BoolP("verbose", "v", false, "verbose")
+	if err := app.AddNamespace("db", db); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name      string
+		namespace string
+		origin    *Flag
+	}{
+		{"db.host", "db", db.Lookup("host")},
+		{"verbose", "", nil},
+	}
+	for _, tc := range cases {
+		namespace, origin := app.Lookup(tc.name).Namespace()
+		if namespace != tc.namespace || origin != tc.origin {
+			t.Errorf("%s: expected the namespace %q and origin %v, got %q and %v", tc.name, tc.namespace, tc.origin, namespace, origin)
+		}
+	}
+	if app.ShorthandLookup("H") != nil {
+		t.Error("expected shorthands not to be namespaced")
+	}
+}
+
+func TestNamespacePresetOrigin(t *testing.T) {
+	db := NewFlagSet("db", ContinueOnError)
+	db.String("host", "localhost", "database host")
+	if err := db.Set("host", "preset"); err != nil {
+		t.Fatal(err)
+	}
+	app := NewFlagSet("app", ContinueOnError)
+	if err := app.AddNamespace("db", db); err != nil {
+		t.Fatal(err)
+	}
+	if app.Changed("db.host") || app.NFlag() != 0 || len(app.Occurrences("db.host")) != 0 {
+		t.Error("expected the namespaced flag to start unchanged")
+	}
+	if host, _ := app.GetString("db.host"); host != "preset" {
+		t.Errorf("expected the namespaced flag to share the value, got %q", host)
+	}
+
+	if err := db.Set("host", "later"); err != nil {
+		t.Fatal(err)
+	}
+	if app.Changed("db.host") {
+		t.Error("expected setting the library not to change the application")
+	}
+}
+
+func TestNamespacesNested(t *testing.T) {
+	db := NewFlagSet("db", ContinueOnError)
+	db.Int("port", 5432, "database port")
+	app := NewFlagSet("app", ContinueOnError)
+	outer := NewFlagSet("outer", ContinueOnError)
+	if err := app.AddNamespace("db", db); err != nil {
+		t.Fatal(err)
+	}
+	if err := outer.AddNamespace("app", app); err != nil {
+		t.Fatal(err)
+	}
+	if err := outer.Parse([]string{"--app.db.port=1"}); err != nil {
+		t.Fatal(err)
+	}
+	if port, _ := db.GetInt("port"); port != 1 || !db.Changed("port") || !app.Changed("db.port") {
+		t.Errorf("expected the innermost flag to be set and changed, got %d", port)
+	}
+}
+
+func TestNamespacesUsage(t *testing.T) {
+	db := NewFlagSet("db", ContinueOnError)
+	db.String("host", "localhost", "database host")
+	db.Int("pool", 4, "connection pool size")
+	_ = db.SetFlagGroup("pool", "Tuning")
+	db.AddGroup("Unused")
+	cache := NewFlagSet("cache", ContinueOnError)
+	cache.Int("size", 64, "cache size in MB")
+
+	app := NewFlagSet("app", ContinueOnError)
+	app.BoolP("verbose", "v", false, "verbose")
+	if err := app.AddNamespace("db", db); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.AddNamespace("cache", cache); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"db", "db/Tuning", "cache"}
+	if !reflect.DeepEqual(app.Groups(), expected) {
+		t.Errorf("expected the groups %q, got %q", expected, app.Groups())
+	}
+	usage := app.FlagUsages()
+	last := -1
+	for _, part := range []string{"db:\n", "      --db.host string", "db/Tuning:\n", "      --db.pool int", "cache:\n", "      --cache.size int"} {
+		i := strings.Index(usage, part)
+		if i < 0 || i < last {
+			t.Errorf("expected %q in usage after the previous parts:\n%s", part, usage)
+		}
+		last = i
+	}
+}
+
+func TestNamespaceEmpty(t *testing.T) {
+	app := NewFlagSet("app", ContinueOnError)
+	if err := app.AddNamespace("db", NewFlagSet("db", ContinueOnError)); err != nil {
+		t.Fatal(err)
+	}
+	if len(app.Groups()) != 0 {
+		t.Errorf("expected no group for an empty namespace, got %q", app.Groups())
+	}
+}
+
+func TestNamespaceConflict(t *testing.T) {
+	cases := []struct {
+		desc     string
+		lib      []string // the flags of the library
+		existing string   // the flag of the application, if any
+		err      string
+	}{
+		{"same name", []string{"host", "port"}, "db.port", "app flag redefined: db.port"},
+		{"same normalized name", []string{"host", "port"}, "db_port", "app flag redefined: db.port"},
+		{"library flags with the same normalized name", []string{"host", "pool.size", "pool_size"}, "",
+			"app flag redefined: db.pool_size"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			db := NewFlagSet("db", ContinueOnError)
+			for _, name := range tc.lib {
+				db.Int(name, 0, name)
+			}
+			app := NewFlagSet("app", ContinueOnError)
+			app.SetNormalizeFunc(func(f *FlagSet, name string) NormalizedName {
+				return NormalizedName(strings.Replace(name, "_", ".", -1))
+			})
+			if tc.existing != "" {
+				app.Int(tc.existing, 0, "existing")
+			}
+
+			err := app.AddNamespace("db", db)
+			if _, ok := err.(*FlagRedefinedError); !ok { //nolint:errorlint
+				t.Errorf("expected a *FlagRedefinedError, got %#v", err)
+			}
+			if err != nil && err.Error() != tc.err {
+				t.Errorf("expected %q, got %q", tc.err, err.Error())
+			}
+			if app.Lookup("db.host") != nil || app.Lookup("db.pool.size") != nil || len(app.Groups()) != 0 {
+				t.Error("expected the flag set to be unchanged")
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package pflag
+
+import "strconv"
+
+// SetNegativeNumbers sets whether arguments which are negative numbers, such
+// as -5, -3.14 or -1e9, are read as non-flag arguments rather than as
+// shorthands. It has no effect if a digit is defined as a shorthand. Negative
+// numbers are always accepted as the value of a flag which requires one,
+// e.g. "--offset -5".
+func (f *FlagSet) SetNegativeNumbers(enabled bool) {
+	f.negativeNumbers = enabled
+}
+
+// isNegativeNumber reports whether the argument s is read as a negative
+// number rather than as a flag.
+func (f *FlagSet) isNegativeNumber(s string) bool {
+	if !f.negativeNumbers || len(s) < 2 || s[0] != '-' {
+		return false
+	}
+	if c := s[1]; !isDigit(c) && !(c == '.' && len(s) > 2 && isDigit(s[2])) {
+		return false
+	}
+	for c := '0'; c <= '9'; c++ {
+		if _, exists := f.shorthands[string(c)]; exists {
+			return false
+		}
+	}
+	if _, err := strconv.ParseInt(s, 0, 64); err == nil || isRangeError(err) {
+		return true
+	}
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil || isRangeError(err)
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// isRangeError reports whether err is a strconv error for a number which is
+// well formed but out of range.
+func isRangeError(err error) bool {
+	numErr, ok := err.(*strconv.NumError)       //nolint:errorlint // not using errors.As for compatibility with go1.12
+	return ok && numErr.Err == strconv.ErrRange //nolint:errorlint // not using errors.Is for compatibility with go1.12
+}
@@ -0,0 +1,71 @@
+package pflag
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestNegativeNumbers(t *testing.T) {
+	f := NewFlagSet("calc", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	f.SetNegativeNumbers(true)
+	verbose := f.BoolP("verbose", "v", false, "verbose")
+	offset := f.IntP("offset", "o", 0, "offset")
+
+	args := []string{"-5", "3", "-3.14", "-v", "-1e9", "-o", "-2", "-.5", "-0x10", "-99999999999999999999"}
+	if err := f.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"-5", "3", "-3.14", "-1e9", "-.5", "-0x10", "-99999999999999999999"}
+	if !reflect.DeepEqual(f.Args(), expected) {
+		t.Errorf("expected %q, got %q", expected, f.Args())
+	}
+	if !*verbose || *offset != -2 {
+		t.Errorf("unexpected flag values: %v %d", *verbose, *offset)
+	}
+}
+
+func TestNegativeNumbersNotNumbers(t *testing.T) {
+	f := NewFlagSet("calc", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	f.SetNegativeNumbers(true)
+	for _, arg := range []string{"-5x", "-Inf", "-.", "-1.2.3"} {
+		if err := f.Parse([]string{arg}); err == nil {
+			t.Errorf("expected %q to be read as shorthands", arg)
+		}
+	}
+}
+
+func TestNegativeNumbersDisabled(t *testing.T) {
+	f := NewFlagSet("calc", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	if err := f.Parse([]string{"-5"}); err == nil {
+		t.Error("expected -5 to be read as a shorthand by default")
+	}
+
+	f = NewFlagSet("calc", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	f.SetNegativeNumbers(true)
+	one := f.BoolP("one", "1", false, "one")
+	if err := f.Parse([]string{"-1", "-5"}); err == nil {
+		t.Error("expected -5 to be read as a shorthand when a digit shorthand is defined")
+	}
+	if !*one {
+		t.Error("expected -1 to set the digit shorthand")
+	}
+}
+
+func TestNegativeNumbersNotInterspersed(t *testing.T) {
+	f := NewFlagSet("calc", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	f.SetNegativeNumbers(true)
+	f.SetInterspersed(false)
+	f.BoolP("verbose", "v", false, "verbose")
+	if err := f.Parse([]string{"-5", "-v"}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(f.Args(), []string{"-5", "-v"}) || f.Changed("verbose") {
+		t.Errorf("expected parsing to stop at -5, got %q", f.Args())
+	}
+}
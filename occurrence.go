@@ -0,0 +1,52 @@
+package pflag
+
+// An Occurrence records one appearance of a flag on the command line.
+type Occurrence struct {
+	Flag        *Flag
+	Arg         string      // the argument as given, e.g. "--verbose", "-vvv" or "--port=80"
+	Name        string      // the name or shorthand the flag was given by
+	Value       string      // the value the flag was set to
+	ValueOrigin ValueOrigin // where Value came from
+	Index       int         // index of the argument in the arguments passed to Parse
+	Shorthand   bool        // the flag was given by its shorthand
+}
+
+// Occurrences returns every occurrence of the named flag set by the last
+// call to Parse or ParseAll, in command-line order. An occurrence whose value
+// was rejected is not recorded. It returns nil if the flag was not given or
+// does not exist.
+func (f *FlagSet) Occurrences(name string) []Occurrence {
+	return f.occurrences[f.normalizeFlagName(name)]
+}
+
+// recordOccurrence records the occurrence of the flag read as token t.
+func (f *FlagSet) recordOccurrence(t Token) {
+	if f.occurrences == nil {
+		f.occurrences = make(map[NormalizedName][]Occurrence)
+	}
+	name := f.normalizeFlagName(t.Flag.Name)
+	f.occurrences[name] = append(f.occurrences[name], t.occurrence())
+}
+
+// occurrence returns the occurrence of the flag read as the token.
+func (t Token) occurrence() Occurrence {
+	return Occurrence{
+		Flag:        t.Flag,
+		Arg:         t.Arg,
+		Name:        t.Name,
+		Value:       t.Value,
+		ValueOrigin: t.ValueOrigin,
+		Index:       t.Index,
+		Shorthand:   t.Kind == ShortFlagToken,
+	}
+}
+
+// String returns the occurrence as it was written on the command line: its
+// argument, followed by its value if it was the next argument, e.g.
+// "--port=80", "-p 80" or "-vp80".
+func (o Occurrence) String() string {
+	if o.ValueOrigin == NextArgValue {
+		return o.Arg + " " + o.Value
+	}
+	return o.Arg
+}
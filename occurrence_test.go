@@ -0,0 +1,81 @@
+package pflag
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestOccurrences(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	f.CountP("verbose", "v", "verbosity")
+	f.IntP("port", "p", 0, "port")
+	f.Bool("quiet", false, "quiet")
+
+	if err := f.Parse([]string{"-vvv", "--port", "80", "arg", "--verbose", "-p8080"}); err != nil {
+		t.Fatal(err)
+	}
+
+	verbose := f.Lookup("verbose")
+	expected := []Occurrence{
+		{Flag: verbose, Arg: "-vvv", Name: "v", Value: "+1", ValueOrigin: NoOptDefValue, Index: 0, Shorthand: true},
+		{Flag: verbose, Arg: "-vvv", Name: "v", Value: "+1", ValueOrigin: NoOptDefValue, Index: 0, Shorthand: true},
+		{Flag: verbose, Arg: "-vvv", Name: "v", Value: "+1", ValueOrigin: NoOptDefValue, Index: 0, Shorthand: true},
+		{Flag: verbose, Arg: "--verbose", Name: "verbose", Value: "+1", ValueOrigin: NoOptDefValue, Index: 4},
+	}
+	if got := f.Occurrences("verbose"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected occurrences of verbose:\n got %+v\nwant %+v", got, expected)
+	}
+
+	port := f.Lookup("port")
+	expected = []Occurrence{
+		{Flag: port, Arg: "--port", Name: "port", Value: "80", ValueOrigin: NextArgValue, Index: 1},
+		{Flag: port, Arg: "-p8080", Name: "p", Value: "8080", ValueOrigin: InlineValue, Index: 5, Shorthand: true},
+	}
+	if got := f.Occurrences("port"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected occurrences of port:\n got %+v\nwant %+v", got, expected)
+	}
+
+	if got := f.Occurrences("quiet"); got != nil {
+		t.Errorf("expected no occurrences of quiet, got %+v", got)
+	}
+	if got := f.Occurrences("missing"); got != nil {
+		t.Errorf("expected no occurrences of an undefined flag, got %+v", got)
+	}
+
+	if err := f.Parse([]string{"--quiet"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Occurrences("port"); got != nil {
+		t.Errorf("expected occurrences to be reset by Parse, got %+v", got)
+	}
+	if got := f.Occurrences("quiet"); len(got) != 1 {
+		t.Errorf("expected one occurrence of quiet, got %+v", got)
+	}
+}
+
+func TestOccurrencesNormalized(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetNormalizeFunc(wordSepNormalizeFunc)
+	f.String("dry-run", "", "dry run")
+	if err := f.Parse([]string{"--dry_run=a", "--dry.run=b"}); err != nil {
+		t.Fatal(err)
+	}
+	occurrences := f.Occurrences("dry_run")
+	if len(occurrences) != 2 || occurrences[0].Name != "dry_run" || occurrences[1].Value != "b" {
+		t.Errorf("unexpected occurrences: %+v", occurrences)
+	}
+}
+
+func TestOccurrencesRejectedValue(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	f.IntP("port", "p", 0, "port")
+	if err := f.Parse([]string{"--port=80", "-p", "http"}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if got := f.Occurrences("port"); len(got) != 1 || got[0].Value != "80" {
+		t.Errorf("expected only the occurrence which was set, got %+v", got)
+	}
+}
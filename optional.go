@@ -0,0 +1,81 @@
+package pflag
+
+import "strings"
+
+// OptionalValuePolicy decides whether a flag with an optional value, one
+// with a NoOptDefVal, takes its value from the argument following it.
+type OptionalValuePolicy int
+
+const (
+	// OptionalValueInline takes the value only from the flag's own argument,
+	// as in --flag=value or -fvalue; "--flag value" leaves value as a
+	// non-flag argument. It is the default.
+	OptionalValueInline OptionalValuePolicy = iota
+	// OptionalValueNextArg also takes the value from the next argument when
+	// the flag's AcceptOptionalValue accepts it.
+	OptionalValueNextArg
+	// OptionalValueStrict fails with an *AmbiguousValueError when the next
+	// argument is accepted by the flag's AcceptOptionalValue, as it could be
+	// meant either as the value or as a non-flag argument.
+	OptionalValueStrict
+)
+
+// SetOptionalValuePolicy sets the optional value policy of the named flag
+// and the function deciding which arguments can be its value. A nil accept
+// function accepts every argument which does not start with a dash.
+func (f *FlagSet) SetOptionalValuePolicy(name string, policy OptionalValuePolicy, accept func(value string) bool) error {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return f.translateError(&NotExistError{name: name, messageType: flagNotExistMessage})
+	}
+	flag.OptionalValuePolicy = policy
+	flag.AcceptOptionalValue = accept
+	return nil
+}
+
+// AcceptValues returns a function for AcceptOptionalValue which accepts the
+// given values only, for flags taking one of a set of values.
+func AcceptValues(values ...string) func(value string) bool {
+	return func(value string) bool {
+		for _, v := range values {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AcceptParsable returns a function for AcceptOptionalValue which accepts
+// the values parse returns no error for, e.g. a strconv based check.
+func AcceptParsable(parse func(value string) error) func(value string) bool {
+	return func(value string) bool {
+		return parse(value) == nil
+	}
+}
+
+// acceptsOptionalValue reports whether the argument can be the optional
+// value of the flag.
+func (f *Flag) acceptsOptionalValue(arg string) bool {
+	if arg == "--" {
+		return false
+	}
+	if f.AcceptOptionalValue == nil {
+		return arg != "" && !strings.HasPrefix(arg, "-")
+	}
+	return f.AcceptOptionalValue(arg)
+}
+
+// takesNextArg reports whether the flag, given without an inline value,
+// takes its value from the first of args according to its optional value
+// policy.
+func (f *FlagSet) takesNextArg(flag *Flag, args []string) (bool, error) {
+	if flag.NoOptDefVal == "" || flag.OptionalValuePolicy == OptionalValueInline ||
+		len(args) == 0 || !flag.acceptsOptionalValue(args[0]) {
+		return false, nil
+	}
+	if flag.OptionalValuePolicy == OptionalValueStrict {
+		return false, &AmbiguousValueError{flag: flag, value: args[0]}
+	}
+	return true, nil
+}
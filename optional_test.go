@@ -0,0 +1,125 @@
+package pflag
+
+import (
+	"bytes"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func setUpOptionalFlagSet(policy OptionalValuePolicy, accept func(string) bool) (*FlagSet, *string) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	color := f.StringP("color", "c", "never", "when to color the output")
+	f.Lookup("color").NoOptDefVal = "auto"
+	f.BoolP("verbose", "v", false, "verbose")
+	if err := f.SetOptionalValuePolicy("color", policy, accept); err != nil {
+		panic(err)
+	}
+	return f, color
+}
+
+func TestOptionalValueInline(t *testing.T) {
+	f, color := setUpOptionalFlagSet(OptionalValueInline, nil)
+	if err := f.Parse([]string{"--color", "always"}); err != nil {
+		t.Fatal(err)
+	}
+	if *color != "auto" || !reflect.DeepEqual(f.Args(), []string{"always"}) {
+		t.Errorf("expected the next argument not to be consumed, got %q %q", *color, f.Args())
+	}
+}
+
+func TestOptionalValueNextArg(t *testing.T) {
+	f, color := setUpOptionalFlagSet(OptionalValueNextArg, AcceptValues("always", "never", "auto"))
+	if err := f.Parse([]string{"--color", "always", "file"}); err != nil {
+		t.Fatal(err)
+	}
+	if *color != "always" || !reflect.DeepEqual(f.Args(), []string{"file"}) {
+		t.Errorf("expected the next argument to be consumed, got %q %q", *color, f.Args())
+	}
+
+	if err := f.Parse([]string{"-vc", "never"}); err != nil {
+		t.Fatal(err)
+	}
+	if *color != "never" || len(f.Args()) != 0 {
+		t.Errorf("expected the shorthand to consume the next argument, got %q %q", *color, f.Args())
+	}
+
+	if err := f.Parse([]string{"--color", "file", "-c", "--", "always"}); err != nil {
+		t.Fatal(err)
+	}
+	if *color != "auto" || !reflect.DeepEqual(f.Args(), []string{"file", "always"}) {
+		t.Errorf("expected rejected arguments not to be consumed, got %q %q", *color, f.Args())
+	}
+}
+
+func TestOptionalValueDefaultAccept(t *testing.T) {
+	f, color := setUpOptionalFlagSet(OptionalValueNextArg, nil)
+	if err := f.Parse([]string{"--color", "-v"}); err != nil {
+		t.Fatal(err)
+	}
+	if *color != "auto" || !f.Changed("verbose") {
+		t.Errorf("expected a flag not to be consumed, got %q", *color)
+	}
+	if err := f.Parse([]string{"--color", "always"}); err != nil {
+		t.Fatal(err)
+	}
+	if *color != "always" {
+		t.Errorf("expected a non-flag argument to be consumed, got %q", *color)
+	}
+}
+
+func TestOptionalValueStrict(t *testing.T) {
+	f, _ := setUpOptionalFlagSet(OptionalValueStrict, nil)
+	err := f.Parse([]string{"--color", "always"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	expected := `ambiguous argument "always" after flag --color: use --color=always to set the flag to it`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+	if _, ok := err.(*AmbiguousValueError); !ok { //nolint:errorlint
+		t.Errorf("expected an *AmbiguousValueError, got %T", err)
+	}
+	if err := f.Parse([]string{"--color=always", "file"}); err != nil {
+		t.Errorf("expected an inline value not to be ambiguous, got %v", err)
+	}
+}
+
+func TestOptionalValueAcceptParsable(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	level := f.Int("level", 0, "level")
+	f.Lookup("level").NoOptDefVal = "1"
+	parseInt := func(s string) error {
+		_, err := strconv.Atoi(s)
+		return err
+	}
+	if err := f.SetOptionalValuePolicy("level", OptionalValueNextArg, AcceptParsable(parseInt)); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Parse([]string{"--level", "-3"}); err != nil || *level != -3 {
+		t.Errorf("expected -3 to be consumed, got %v %d", err, *level)
+	}
+	if err := f.Parse([]string{"--level", "x"}); err != nil || *level != 1 || f.Arg(0) != "x" {
+		t.Errorf("expected x not to be consumed, got %v %d %q", err, *level, f.Args())
+	}
+	if err := f.SetOptionalValuePolicy("missing", OptionalValueNextArg, nil); err == nil {
+		t.Error("expected an error for an undefined flag")
+	}
+}
+
+func TestOptionalValueUsage(t *testing.T) {
+	f, _ := setUpOptionalFlagSet(OptionalValueNextArg, nil)
+	usage := f.FlagUsages()
+	if !strings.Contains(usage, `-c, --color [string="auto"]`) {
+		t.Errorf("expected an optional value in usage, got:\n%s", usage)
+	}
+	f, _ = setUpOptionalFlagSet(OptionalValueInline, nil)
+	usage = f.FlagUsages()
+	if !strings.Contains(usage, `-c, --color string[="auto"]`) {
+		t.Errorf("expected the inline optional value in usage, got:\n%s", usage)
+	}
+}
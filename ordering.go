@@ -0,0 +1,59 @@
+package pflag
+
+import "os"
+
+// Ordering decides how flags and non-flag arguments may be mixed on the
+// command line. The three orderings are those of GNU getopt.
+type Ordering int
+
+const (
+	// PermuteOrder accepts flags anywhere before the "--" terminator and
+	// collects the non-flag arguments in Args(). It is the default.
+	PermuteOrder Ordering = iota
+	// RequireOrder stops parsing flags at the first non-flag argument, like
+	// getopt with POSIXLY_CORRECT set or an optstring starting with '+'.
+	// It is equivalent to SetInterspersed(false).
+	RequireOrder
+	// ReturnInOrder accepts flags anywhere, like PermuteOrder, and also
+	// passes every non-flag argument before the "--" terminator to the
+	// function given to ParseAll, in command-line order and with a nil flag,
+	// like getopt with an optstring starting with '-'. The arguments are
+	// still collected in Args().
+	ReturnInOrder
+)
+
+// DefaultOrdering returns the ordering GNU getopt uses by default:
+// RequireOrder if the POSIXLY_CORRECT environment variable is set, and
+// PermuteOrder otherwise. A FlagSet created with NewFlagSet uses
+// PermuteOrder; programs honoring POSIXLY_CORRECT opt in with
+// f.SetOrdering(DefaultOrdering()).
+func DefaultOrdering() Ordering {
+	if _, set := os.LookupEnv("POSIXLY_CORRECT"); set {
+		return RequireOrder
+	}
+	return PermuteOrder
+}
+
+// SetOrdering sets how flags and non-flag arguments may be mixed. The
+// default is DefaultOrdering().
+func (f *FlagSet) SetOrdering(ordering Ordering) {
+	f.interspersed = ordering != RequireOrder
+	f.returnInOrder = ordering == ReturnInOrder
+}
+
+// GetOrdering returns how flags and non-flag arguments may be mixed.
+func (f *FlagSet) GetOrdering() Ordering {
+	switch {
+	case !f.interspersed:
+		return RequireOrder
+	case f.returnInOrder:
+		return ReturnInOrder
+	}
+	return PermuteOrder
+}
+
+// SetOrdering sets how flags and non-flag arguments may be mixed on the
+// command line.
+func SetOrdering(ordering Ordering) {
+	CommandLine.SetOrdering(ordering)
+}
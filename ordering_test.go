@@ -0,0 +1,113 @@
+package pflag
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func setUpOrderingFlagSet(ordering Ordering) *FlagSet {
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	f.SetOrdering(ordering)
+	f.BoolP("all", "a", false, "all")
+	f.StringP("file", "f", "", "file")
+	return f
+}
+
+func TestPermuteOrder(t *testing.T) {
+	f := setUpOrderingFlagSet(PermuteOrder)
+	if err := f.Parse([]string{"x", "-a", "y", "--file=z", "--", "-a"}); err != nil {
+		t.Fatal(err)
+	}
+	if !f.Changed("all") || !f.Changed("file") {
+		t.Error("expected flags after non-flag arguments to be parsed")
+	}
+	if !reflect.DeepEqual(f.Args(), []string{"x", "y", "-a"}) {
+		t.Errorf("unexpected args: %q", f.Args())
+	}
+	if f.GetOrdering() != PermuteOrder {
+		t.Errorf("expected PermuteOrder, got %v", f.GetOrdering())
+	}
+}
+
+func TestRequireOrder(t *testing.T) {
+	f := setUpOrderingFlagSet(RequireOrder)
+	if err := f.Parse([]string{"-a", "x", "--file=z"}); err != nil {
+		t.Fatal(err)
+	}
+	if !f.Changed("all") || f.Changed("file") {
+		t.Error("expected parsing to stop at the first non-flag argument")
+	}
+	if !reflect.DeepEqual(f.Args(), []string{"x", "--file=z"}) {
+		t.Errorf("unexpected args: %q", f.Args())
+	}
+
+	f = setUpOrderingFlagSet(PermuteOrder)
+	f.SetInterspersed(false)
+	if f.GetOrdering() != RequireOrder {
+		t.Errorf("expected SetInterspersed(false) to mean RequireOrder, got %v", f.GetOrdering())
+	}
+}
+
+func TestReturnInOrder(t *testing.T) {
+	f := setUpOrderingFlagSet(ReturnInOrder)
+	var seen []string
+	fn := func(flag *Flag, value string) error {
+		if flag == nil {
+			seen = append(seen, value)
+			return nil
+		}
+		seen = append(seen, flag.Name+"="+value)
+		return f.Set(flag.Name, value)
+	}
+	if err := f.ParseAll([]string{"x", "-a", "y", "-f", "z", "--", "w"}, fn); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"x", "all=true", "y", "file=z"}
+	if !reflect.DeepEqual(seen, expected) {
+		t.Errorf("expected %q, got %q", expected, seen)
+	}
+	if !reflect.DeepEqual(f.Args(), []string{"x", "y", "w"}) {
+		t.Errorf("unexpected args: %q", f.Args())
+	}
+
+	f = setUpOrderingFlagSet(ReturnInOrder)
+	if err := f.Parse([]string{"x", "-a"}); err != nil || !f.Changed("all") || f.Arg(0) != "x" {
+		t.Errorf("expected Parse to work with ReturnInOrder, got %v %q", err, f.Args())
+	}
+}
+
+func TestDefaultOrdering(t *testing.T) {
+	value, set := os.LookupEnv("POSIXLY_CORRECT")
+	defer func() {
+		if set {
+			_ = os.Setenv("POSIXLY_CORRECT", value)
+		} else {
+			_ = os.Unsetenv("POSIXLY_CORRECT")
+		}
+	}()
+
+	_ = os.Unsetenv("POSIXLY_CORRECT")
+	if o := DefaultOrdering(); o != PermuteOrder {
+		t.Errorf("expected PermuteOrder, got %v", o)
+	}
+	_ = os.Setenv("POSIXLY_CORRECT", "")
+	if o := DefaultOrdering(); o != RequireOrder {
+		t.Errorf("expected RequireOrder with POSIXLY_CORRECT set, got %v", o)
+	}
+	if o := NewFlagSet("test", ContinueOnError).GetOrdering(); o != PermuteOrder {
+		t.Errorf("expected a new FlagSet to keep PermuteOrder, got %v", o)
+	}
+
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetOrdering(DefaultOrdering())
+	f.Bool("all", false, "all")
+	if err := f.Parse([]string{"x", "--all"}); err != nil {
+		t.Fatal(err)
+	}
+	if f.Changed("all") || !reflect.DeepEqual(f.Args(), []string{"x", "--all"}) {
+		t.Errorf("expected parsing to stop at the first non-flag argument, got %q", f.Args())
+	}
+}
@@ -0,0 +1,210 @@
+package pflag
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A Positional represents a named positional argument, bound to one of the
+// non-flag arguments left after flag parsing.
+type Positional struct {
+	Name     string // name as shown in the synopsis and usage
+	Usage    string // help message
+	Value    Value  // value as set
+	Required bool   // Parse fails if the argument is missing
+	Variadic bool   // the argument receives all remaining arguments; must be the last one
+	Changed  bool   // If the argument was given on the command line
+}
+
+// AddPositional adds a positional argument to the FlagSet. Positional
+// arguments are bound in the order they are added: required ones must come
+// before optional ones, and a variadic one must be the last.
+func (f *FlagSet) AddPositional(arg *Positional) {
+	for _, p := range f.positionals {
+		msg := ""
+		switch {
+		case p.Name == arg.Name:
+			msg = fmt.Sprintf("%s positional argument redefined: %s", f.name, arg.Name)
+		case p.Variadic:
+			msg = fmt.Sprintf("positional argument %q defined after variadic argument %q", arg.Name, p.Name)
+		case arg.Required && !p.Required:
+			msg = fmt.Sprintf("required positional argument %q defined after optional argument %q", arg.Name, p.Name)
+		}
+		if msg != "" {
+			_, _ = fmt.Fprintln(f.Output(), msg)
+			panic(msg)
+		}
+	}
+	f.positionals = append(f.positionals, arg)
+}
+
+// PositionalVar defines a required positional argument with the specified
+// name and usage string, stored in value.
+func (f *FlagSet) PositionalVar(value Value, name, usage string) {
+	f.AddPositional(&Positional{Name: name, Usage: usage, Value: value, Required: true})
+}
+
+// OptionalPositionalVar defines an optional positional argument with the
+// specified name and usage string, stored in value.
+func (f *FlagSet) OptionalPositionalVar(value Value, name, usage string) {
+	f.AddPositional(&Positional{Name: name, Usage: usage, Value: value})
+}
+
+// VariadicPositionalVar defines a positional argument which receives all
+// remaining arguments. If value is a SliceValue its contents are replaced
+// with the arguments, otherwise Set is called for each of them. If required
+// is true, at least one argument must be given.
+func (f *FlagSet) VariadicPositionalVar(value Value, name, usage string, required bool) {
+	f.AddPositional(&Positional{Name: name, Usage: usage, Value: value, Required: required, Variadic: true})
+}
+
+// Positionals returns the positional arguments defined in the FlagSet.
+func (f *FlagSet) Positionals() []*Positional {
+	return f.positionals
+}
+
+// LookupPositional returns the named positional argument, returning nil if
+// none exists.
+func (f *FlagSet) LookupPositional(name string) *Positional {
+	for _, p := range f.positionals {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// bindPositionals sets the positional arguments from the non-flag arguments.
+// Arguments after "--" can fill positional arguments, which is how values
+// starting with a dash are passed, but arguments after "--" which are left
+// over once all positional arguments are bound are not an error. Args()
+// still returns all non-flag arguments. The unknown flags passed to Args()
+// with PassUnknownFlagToArgs or PassUnknownFlagToArgsInPlace are not bound.
+func (f *FlagSet) bindPositionals() error {
+	if len(f.positionals) == 0 {
+		return nil
+	}
+
+	operands, lenAtDash := f.operands()
+	args := operands
+	for _, p := range f.positionals {
+		if len(args) == 0 {
+			if p.Required {
+				return f.fail(&ArityError{positional: p, minArgs: f.minPositionals(), actual: len(operands)})
+			}
+			break
+		}
+
+		values := args[:1]
+		if p.Variadic {
+			values = args
+		}
+		args = args[len(values):]
+
+		if err := p.set(values); err != nil {
+			return f.fail(err)
+		}
+	}
+
+	bound := len(operands) - len(args)
+	if len(args) > 0 && (lenAtDash < 0 || bound < lenAtDash) {
+		return f.fail(&ArityError{maxArgs: len(f.positionals), actual: len(operands)})
+	}
+	return nil
+}
+
+// operands returns the non-flag arguments without the unknown flags passed
+// to them, and the number of them before "--", or -1 if there is no "--".
+func (f *FlagSet) operands() ([]string, int) {
+	if len(f.passedArgs) == 0 {
+		return f.args, f.argsLenAtDash
+	}
+	operands := make([]string, 0, len(f.args))
+	lenAtDash := -1
+	for i, arg := range f.args {
+		if i == f.argsLenAtDash {
+			lenAtDash = len(operands)
+		}
+		if !f.passedArgs[i] {
+			operands = append(operands, arg)
+		}
+	}
+	if f.argsLenAtDash == len(f.args) {
+		lenAtDash = len(operands)
+	}
+	return operands, lenAtDash
+}
+
+// set binds the given command-line arguments to the positional argument.
+func (p *Positional) set(values []string) error {
+	if sv, ok := p.Value.(SliceValue); ok && p.Variadic {
+		if err := sv.Replace(values); err != nil {
+			return &InvalidPositionalError{positional: p, value: strings.Join(values, " "), cause: err}
+		}
+		p.Changed = true
+		return nil
+	}
+	for _, v := range values {
+		if err := p.Value.Set(v); err != nil {
+			return &InvalidPositionalError{positional: p, value: v, cause: err}
+		}
+	}
+	p.Changed = true
+	return nil
+}
+
+// minPositionals returns the number of required positional arguments.
+func (f *FlagSet) minPositionals() int {
+	n := 0
+	for _, p := range f.positionals {
+		if p.Required {
+			n++
+		}
+	}
+	return n
+}
+
+// synopsisName returns how the positional argument is shown in the synopsis,
+// e.g. "<src>", "[dest]" or "[files...]".
+func (p *Positional) synopsisName() string {
+	name := p.Name
+	if p.Variadic {
+		name += "..."
+	}
+	if p.Required {
+		return "<" + name + ">"
+	}
+	return "[" + name + "]"
+}
+
+// Synopsis returns a one-line summary of how the FlagSet is invoked, e.g.
+// "cp [flags] <src> <dest>".
+func (f *FlagSet) Synopsis() string {
+	parts := []string{f.name}
+	if f.HasAvailableFlags() {
+		parts = append(parts, "[flags]")
+	}
+	for _, p := range f.positionals {
+		parts = append(parts, p.synopsisName())
+	}
+	return strings.Join(parts, " ")
+}
+
+// PositionalUsages returns a string containing the usage information for the
+// positional arguments of the FlagSet.
+func (f *FlagSet) PositionalUsages() string {
+	buf := new(bytes.Buffer)
+	maxlen := 0
+	for _, p := range f.positionals {
+		if w := displayWidth(p.synopsisName()); w > maxlen {
+			maxlen = w
+		}
+	}
+	for _, p := range f.positionals {
+		name := p.synopsisName()
+		spacing := strings.Repeat(" ", maxlen-displayWidth(name)+3)
+		_, _ = fmt.Fprintf(buf, "  %s%s%s\n", name, spacing, p.Usage)
+	}
+	return buf.String()
+}
@@ -0,0 +1,170 @@
+package pflag
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func setUpPositionalFlagSet() (*FlagSet, *string, *int, *[]string) {
+	var src string
+	var count int
+	var rest []string
+	f := NewFlagSet("cp", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	f.BoolP("verbose", "v", false, "verbose output")
+	f.PositionalVar(newStringValue("", &src), "src", "source file")
+	f.OptionalPositionalVar(newIntValue(1, &count), "count", "number of copies")
+	f.VariadicPositionalVar(newStringSliceValue([]string{}, &rest), "dest", "destinations", false)
+	return f, &src, &count, &rest
+}
+
+func TestPositionals(t *testing.T) {
+	f, src, count, rest := setUpPositionalFlagSet()
+	if err := f.Parse([]string{"a.txt", "-v", "3", "b", "c,d"}); err != nil {
+		t.Fatal(err)
+	}
+	if *src != "a.txt" || *count != 3 || !reflect.DeepEqual(*rest, []string{"b", "c,d"}) {
+		t.Errorf("unexpected values: %q %d %q", *src, *count, *rest)
+	}
+	if !f.LookupPositional("dest").Changed {
+		t.Error("expected dest to be changed")
+	}
+	if !reflect.DeepEqual(f.Args(), []string{"a.txt", "3", "b", "c,d"}) {
+		t.Errorf("expected Args to still return all arguments, got %q", f.Args())
+	}
+}
+
+func TestPositionalsOptionalMissing(t *testing.T) {
+	f, src, count, rest := setUpPositionalFlagSet()
+	if err := f.Parse([]string{"a.txt"}); err != nil {
+		t.Fatal(err)
+	}
+	if *src != "a.txt" || *count != 1 || len(*rest) != 0 {
+		t.Errorf("unexpected values: %q %d %q", *src, *count, *rest)
+	}
+	if f.LookupPositional("count").Changed {
+		t.Error("expected count not to be changed")
+	}
+}
+
+func TestPositionalsArity(t *testing.T) {
+	f, _, _, _ := setUpPositionalFlagSet()
+	err := f.Parse([]string{"-v"})
+	arityErr, ok := err.(*ArityError)
+	if !ok {
+		t.Fatalf("expected an ArityError, got %v", err)
+	}
+	if arityErr.GetPositional().Name != "src" || arityErr.GetMin() != 1 || err.Error() != "missing required argument: <src>" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	var a, b string
+	f = NewFlagSet("test", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	f.PositionalVar(newStringValue("", &a), "a", "")
+	f.OptionalPositionalVar(newStringValue("", &b), "b", "")
+	err = f.Parse([]string{"1", "2", "3"})
+	if _, ok := err.(*ArityError); !ok || err.Error() != "too many arguments: expected at most 2, got 3" {
+		t.Errorf("expected too many arguments error, got %v", err)
+	}
+}
+
+func TestPositionalsDash(t *testing.T) {
+	var a, b string
+	f := NewFlagSet("test", ContinueOnError)
+	f.PositionalVar(newStringValue("", &a), "a", "")
+	f.PositionalVar(newStringValue("", &b), "b", "")
+
+	// Arguments after -- can fill positionals, and left over ones are passed through.
+	if err := f.Parse([]string{"x", "--", "-y", "child", "--arg"}); err != nil {
+		t.Fatal(err)
+	}
+	if a != "x" || b != "-y" {
+		t.Errorf("unexpected values: %q %q", a, b)
+	}
+	if f.ArgsLenAtDash() != 1 {
+		t.Errorf("expected ArgsLenAtDash 1, got %d", f.ArgsLenAtDash())
+	}
+}
+
+func TestPositionalsPassedUnknownFlags(t *testing.T) {
+	for _, handling := range []UnknownFlagsHandling{PassUnknownFlagToArgs, PassUnknownFlagToArgsInPlace} {
+		f, src, count, rest := setUpPositionalFlagSet()
+		f.ParseErrorsAllowlist.UnknownFlagsHandling = handling
+		if err := f.Parse([]string{"--unknown", "a.txt", "-vx", "2", "b"}); err != nil {
+			t.Fatal(err)
+		}
+		if *src != "a.txt" || *count != 2 || !reflect.DeepEqual(*rest, []string{"b"}) {
+			t.Errorf("%v: expected the unknown flags not to be bound, got %q %d %q", handling, *src, *count, *rest)
+		}
+		if len(f.Args()) != 5 {
+			t.Errorf("%v: expected Args to still return the unknown flags, got %q", handling, f.Args())
+		}
+	}
+}
+
+func TestPositionalsInvalidValue(t *testing.T) {
+	f, _, _, _ := setUpPositionalFlagSet()
+	err := f.Parse([]string{"a", "many"})
+	posErr, ok := err.(*InvalidPositionalError)
+	if !ok {
+		t.Fatalf("expected an InvalidPositionalError, got %v", err)
+	}
+	if posErr.GetPositional().Name != "count" || posErr.GetValue() != "many" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPositionalsDefinitionOrder(t *testing.T) {
+	tests := map[string]func(f *FlagSet){
+		"required after optional": func(f *FlagSet) {
+			f.OptionalPositionalVar(newStringValue("", new(string)), "a", "")
+			f.PositionalVar(newStringValue("", new(string)), "b", "")
+		},
+		"after variadic": func(f *FlagSet) {
+			f.VariadicPositionalVar(newStringSliceValue(nil, new([]string)), "a", "", false)
+			f.OptionalPositionalVar(newStringValue("", new(string)), "b", "")
+		},
+		"redefined": func(f *FlagSet) {
+			f.PositionalVar(newStringValue("", new(string)), "a", "")
+			f.PositionalVar(newStringValue("", new(string)), "a", "")
+		},
+	}
+	for name, define := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			f := NewFlagSet("test", ContinueOnError)
+			f.SetOutput(&bytes.Buffer{})
+			define(f)
+		}()
+	}
+}
+
+func TestPositionalsUsage(t *testing.T) {
+	f, _, _, _ := setUpPositionalFlagSet()
+	if s := f.Synopsis(); s != "cp [flags] <src> [count] [dest...]" {
+		t.Errorf("unexpected synopsis %q", s)
+	}
+
+	var buf bytes.Buffer
+	f.SetOutput(&buf)
+	defaultUsage(f)
+	expected := `Usage of cp:
+  cp [flags] <src> [count] [dest...]
+
+  -v, --verbose[=true|false]   verbose output
+
+Arguments:
+  <src>       source file
+  [count]     number of copies
+  [dest...]   destinations
+`
+	if buf.String() != expected {
+		t.Errorf("Expected \n%s \nActual \n%s", expected, buf.String())
+	}
+}
@@ -0,0 +1,139 @@
+package pflag
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestTryAddFlag(t *testing.T) {
+	cases := []struct {
+		desc      string
+		name      string
+		shorthand string
+		want      string // expected error message; empty if the flag is added
+		errType   error
+	}{
+		{"redefined", "verbose", "", "test flag redefined: verbose", &FlagRedefinedError{}},
+		{"shorthand conflict", "version", "v",
+			`unable to redefine 'v' shorthand in "test" flagset: it's already used for "verbose" flag`,
+			&ShorthandConflictError{}},
+		{"invalid shorthand", "name", "nm", `"nm" shorthand is more than one ASCII character`, &InvalidShorthandError{}},
+		{"added", "name", "n", "", nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f := NewFlagSet("test", ContinueOnError)
+			f.SetOutput(ioutil.Discard)
+			f.BoolP("verbose", "v", false, "verbose")
+			existing := f.Lookup("verbose")
+
+			var s string
+			err := f.TryVarP(newStringValue("", &s), tc.name, tc.shorthand, "usage")
+			if tc.want == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if f.ShorthandLookup(tc.shorthand) != f.Lookup(tc.name) {
+					t.Error("expected the flag to be added")
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if reflect.TypeOf(err) != reflect.TypeOf(tc.errType) {
+				t.Errorf("expected a %T, got %#v", tc.errType, err)
+			}
+			if err.Error() != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, err.Error())
+			}
+			switch e := err.(type) { //nolint:errorlint
+			case *FlagRedefinedError:
+				if e.GetExisting() != existing || e.GetFlag().Usage != "usage" {
+					t.Errorf("unexpected flags in %#v", e)
+				}
+			case *ShorthandConflictError:
+				if e.GetExisting() != existing || e.GetFlag().Name != tc.name {
+					t.Errorf("unexpected flags in %#v", e)
+				}
+			}
+			if f.Lookup("verbose") != existing || (tc.name != "verbose" && f.Lookup(tc.name) != nil) {
+				t.Error("expected the flag set to be unchanged after an error")
+			}
+		})
+	}
+}
+
+func TestAddFlagPanics(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	out := &bytes.Buffer{}
+	f.SetOutput(out)
+	f.BoolP("verbose", "v", false, "verbose")
+	defer func() {
+		if r := recover(); r != "test flag redefined: verbose" {
+			t.Errorf("unexpected panic: %v", r)
+		}
+		if out.String() != "test flag redefined: verbose\n" {
+			t.Errorf("unexpected output: %q", out.String())
+		}
+	}()
+	f.Bool("verbose", false, "verbose")
+}
+
+func TestTryAddFlagSet(t *testing.T) {
+	cases := []struct {
+		desc  string
+		flags func(*FlagSet)
+		added []string
+		errs  []string
+	}{
+		{"no conflict", func(p *FlagSet) { p.Int("retries", 3, "retries") }, []string{"retries"}, nil},
+		{"name conflict", func(p *FlagSet) { p.String("config", "", "plugin config") }, nil,
+			[]string{"host flag redefined: config"}},
+		{"shorthand conflict", func(p *FlagSet) { p.BoolP("version", "v", false, "version") }, nil,
+			[]string{`unable to redefine 'v' shorthand in "host" flagset: it's already used for "verbose" flag`}},
+		{"mixed", func(p *FlagSet) {
+			p.String("config", "", "plugin config")
+			p.BoolP("version", "v", false, "version")
+			p.Int("retries", 3, "retries")
+		}, []string{"retries"}, []string{
+			"host flag redefined: config",
+			`unable to redefine 'v' shorthand in "host" flagset: it's already used for "verbose" flag`,
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f := NewFlagSet("host", ContinueOnError)
+			f.BoolP("verbose", "v", false, "verbose")
+			f.String("config", "", "config")
+			plugin := NewFlagSet("plugin", ContinueOnError)
+			tc.flags(plugin)
+
+			var errs []string
+			for _, err := range f.TryAddFlagSet(plugin) {
+				errs = append(errs, err.Error())
+			}
+			if !reflect.DeepEqual(errs, tc.errs) {
+				t.Errorf("expected errors %q, got %q", tc.errs, errs)
+			}
+			var added []string
+			f.VisitAll(func(flag *Flag) {
+				if flag == plugin.Lookup(flag.Name) {
+					added = append(added, flag.Name)
+				}
+			})
+			if !reflect.DeepEqual(added, tc.added) {
+				t.Errorf("expected the flags %q to be added, got %q", tc.added, added)
+			}
+			if f.Lookup("config").Usage != "config" || f.Lookup("version") != nil {
+				t.Error("expected the conflicting flags not to be added")
+			}
+
+			if errs := f.TryAddFlagSet(plugin); len(errs) != len(tc.errs) {
+				t.Errorf("expected the added flags to be skipped, got %v", errs)
+			}
+		})
+	}
+}
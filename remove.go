@@ -0,0 +1,83 @@
+package pflag
+
+// RemoveFlag removes the named flag from the FlagSet, so that it is no
+// longer parsed, looked up or shown in usage, and forgets whether it was
+// given on the command line. A removed flag can be added back with
+// RestoreFlag. The copies of the flag added to other FlagSets with
+// AddNamespace are not removed: they still set the value shared with the
+// flag, but no longer mark it as changed in f.
+func (f *FlagSet) RemoveFlag(name string) error {
+	normalName := f.normalizeFlagName(name)
+	flag, exists := f.formal[normalName]
+	if !exists {
+		return f.translateError(&NotExistError{name: name, messageType: flagNotExistMessage})
+	}
+	f.removeFlag(flag)
+	if f.removed == nil {
+		f.removed = make(map[NormalizedName]*Flag)
+	}
+	f.removed[normalName] = flag
+	return nil
+}
+
+// RestoreFlag adds back a flag removed with RemoveFlag, with its shorthand
+// and value. It returns a *NotExistError if no such flag was removed, and
+// the errors of TryAddFlag if its name or shorthand has been used since.
+func (f *FlagSet) RestoreFlag(name string) error {
+	normalName := f.normalizeFlagName(name)
+	flag, removed := f.removed[normalName]
+	if !removed {
+		return f.translateError(&NotExistError{name: name, messageType: flagNotExistMessage})
+	}
+	if err := f.TryAddFlag(flag); err != nil {
+		return err
+	}
+	delete(f.removed, normalName)
+	if flag.Changed {
+		flag.Changed = false
+		f.markChanged(normalName, flag)
+	}
+	return nil
+}
+
+// removeFlag removes the flag from the FlagSet, forgetting whether and how
+// it was given on the command line.
+func (f *FlagSet) removeFlag(flag *Flag) {
+	name := f.normalizeFlagName(flag.Name)
+	delete(f.formal, name)
+	f.orderedFormal = removeFromFlags(f.orderedFormal, flag)
+	f.sortedFormal = nil
+	if flag.Shorthand != "" && f.shorthands[flag.Shorthand] == flag {
+		delete(f.shorthands, flag.Shorthand)
+		f.updateMaxShorthandLen()
+	}
+	if _, changed := f.actual[name]; changed {
+		delete(f.actual, name)
+		f.orderedActual = removeFromFlags(f.orderedActual, flag)
+		f.sortedActual = nil
+	}
+	delete(f.occurrences, name)
+}
+
+// updateMaxShorthandLen recomputes the length of the longest shorthand once
+// one has been removed.
+func (f *FlagSet) updateMaxShorthandLen() {
+	f.maxShorthandLen = 0
+	for shorthand := range f.shorthands {
+		if len(shorthand) > f.maxShorthandLen {
+			f.maxShorthandLen = len(shorthand)
+		}
+	}
+}
+
+// removeFromFlags returns a copy of flags without flag, keeping the order.
+// flags is left unchanged for the Visit and VisitAll calls iterating on it.
+func removeFromFlags(flags []*Flag, flag *Flag) []*Flag {
+	kept := make([]*Flag, 0, len(flags))
+	for _, fl := range flags {
+		if fl != flag {
+			kept = append(kept, fl)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,211 @@
+package pflag
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRemoveFlag(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	f.BoolP("verbose", "v", false, "verbose")
+	f.StringP("unsupported", "u", "", "an option the application does not support")
+	f.Int("port", 80, "port")
+
+	if err := f.Parse([]string{"-u", "x", "--port=8080"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.RemoveFlag("unsupported"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.RemoveFlag("port"); err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Lookup("unsupported") != nil || f.ShorthandLookup("u") != nil {
+		t.Error("expected the flag to be removed")
+	}
+	if f.NFlag() != 0 || f.Changed("port") {
+		t.Errorf("expected the removed flags not to be changed, got %d", f.NFlag())
+	}
+	names := []string{}
+	f.VisitAll(func(flag *Flag) {
+		names = append(names, flag.Name)
+	})
+	if strings.Join(names, ",") != "verbose" {
+		t.Errorf("expected only verbose to be visited, got %q", names)
+	}
+	if usage := f.FlagUsages(); strings.Contains(usage, "unsupported") {
+		t.Errorf("expected the removed flag not to be in usage:\n%s", usage)
+	}
+	if err := f.Parse([]string{"-u"}); err == nil {
+		t.Error("expected the removed shorthand to be unknown")
+	}
+	if err := f.RemoveFlag("unsupported"); err == nil {
+		t.Error("expected an error removing an undefined flag")
+	}
+}
+
+func TestRestoreFlag(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	port := f.IntP("port", "p", 80, "port")
+
+	if err := f.Parse([]string{"-p", "8080"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.RemoveFlag("port"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.RestoreFlag("port"); err != nil {
+		t.Fatal(err)
+	}
+	if f.ShorthandLookup("p") != f.Lookup("port") || *port != 8080 || !f.Changed("port") || f.NFlag() != 1 {
+		t.Error("expected the flag to be restored with its shorthand, value and changed state")
+	}
+	if err := f.RestoreFlag("port"); err == nil {
+		t.Error("expected an error restoring a flag which was not removed")
+	}
+
+	if err := f.RemoveFlag("port"); err != nil {
+		t.Fatal(err)
+	}
+	f.Int("port", 0, "another port")
+	err := f.RestoreFlag("port")
+	if _, ok := err.(*FlagRedefinedError); !ok { //nolint:errorlint
+		t.Errorf("expected a *FlagRedefinedError restoring a flag whose name is used again, got %#v", err)
+	}
+	if err := f.RemoveFlag("port"); err != nil {
+		t.Fatal(err)
+	}
+	f.Int("other", 0, "other")
+	if err := f.RestoreFlag("port"); err != nil || f.Lookup("port").Usage != "another port" {
+		t.Errorf("expected the last flag removed to be restored, got %v", err)
+	}
+	if err := f.RestoreFlag("port"); err == nil {
+		t.Error("expected an error restoring a restored flag")
+	}
+}
+
+func TestRemoveFlagReAdd(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	f.BoolP("verbose", "v", false, "verbose")
+	verbose := f.Lookup("verbose")
+	if err := f.RemoveFlag("verbose"); err != nil {
+		t.Fatal(err)
+	}
+	f.AddFlag(verbose)
+	if err := f.Parse([]string{"-v"}); err != nil {
+		t.Fatal(err)
+	}
+	if !f.Changed("verbose") {
+		t.Error("expected the flag added again to be parsed")
+	}
+	if err := f.RestoreFlag("verbose"); err == nil {
+		t.Error("expected a flag added again not to be restorable")
+	}
+}
+
+func TestRemoveFlagShorthandLen(t *testing.T) {
+	cases := []struct {
+		remove   string
+		expected int
+	}{
+		{"classpath", 1},
+		{"verbose", 2},
+	}
+	for _, tc := range cases {
+		f := NewFlagSet("test", ContinueOnError)
+		f.SetMultiCharShorthands(true)
+		f.StringP("classpath", "cp", "", "class path")
+		f.BoolP("verbose", "v", false, "verbose")
+		if err := f.RemoveFlag(tc.remove); err != nil {
+			t.Fatal(err)
+		}
+		if f.maxShorthandLen != tc.expected {
+			t.Errorf("%s: expected the longest shorthand to be %d bytes, got %d", tc.remove, tc.expected, f.maxShorthandLen)
+		}
+	}
+}
+
+func TestRemoveRestoreFlagGroup(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.Bool("alpha", false, "alpha")
+	f.String("host", "", "host")
+	f.Int("port", 80, "port")
+	_ = f.SetFlagGroup("host", "Networking")
+	_ = f.SetFlagGroup("port", "Networking")
+	usage := f.FlagUsages()
+
+	if err := f.RemoveFlag("host"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.RestoreFlag("host"); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	f.VisitAll(func(flag *Flag) { names = append(names, flag.Name) })
+	if strings.Join(names, ",") != "alpha,host,port" {
+		t.Errorf("expected the flags in sorted order, got %q", names)
+	}
+	if f.Lookup("host").Group != "Networking" {
+		t.Errorf("expected the restored flag to keep its group, got %q", f.Lookup("host").Group)
+	}
+	if f.FlagUsages() != usage {
+		t.Errorf("expected the usage to be unchanged, got:\n%s", f.FlagUsages())
+	}
+}
+
+func TestRemoveNamespacedFlag(t *testing.T) {
+	db := NewFlagSet("db", ContinueOnError)
+	host := db.String("host", "localhost", "database host")
+	app := NewFlagSet("app", ContinueOnError)
+	if err := app.AddNamespace("db", db); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.RemoveFlag("host"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Parse([]string{"--db.host=x"}); err != nil {
+		t.Fatal(err)
+	}
+	if *host != "x" || !app.Changed("db.host") {
+		t.Errorf("expected the namespaced copy to still set the value, got %q", *host)
+	}
+	if db.NFlag() != 0 || db.Lookup("host") != nil {
+		t.Error("expected the removed flag not to be marked as changed")
+	}
+}
+
+func TestRemoveFlagWhileVisiting(t *testing.T) {
+	visits := map[string]func(f *FlagSet, fn func(*Flag)){
+		"VisitAll": (*FlagSet).VisitAll,
+		"Visit":    (*FlagSet).Visit,
+	}
+	for name, visit := range visits {
+		f := NewFlagSet("test", ContinueOnError)
+		f.SortFlags = false
+		for _, flag := range []string{"a", "b", "c", "d"} {
+			f.Bool(flag, false, flag)
+		}
+		if err := f.Parse([]string{"--a", "--b", "--c", "--d"}); err != nil {
+			t.Fatal(err)
+		}
+
+		var names []string
+		visit(f, func(flag *Flag) {
+			names = append(names, flag.Name)
+			if flag.Name == "a" {
+				if err := f.RemoveFlag("a"); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+		if strings.Join(names, ",") != "a,b,c,d" {
+			t.Errorf("%s: expected every flag to be visited once, got %q", name, names)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package pflag
+
+import "fmt"
+
+// RepeatPolicy decides what happens when a flag which takes a single value
+// is given more than once.
+type RepeatPolicy int
+
+const (
+	// RepeatAllow lets the last occurrence of the flag win.
+	RepeatAllow RepeatPolicy = iota
+	// RepeatWarn lets the last occurrence win and prints a warning to Output().
+	RepeatWarn
+	// RepeatError makes parsing fail with a *RepeatedFlagError.
+	RepeatError
+)
+
+// SetRepeatPolicy sets what happens when a flag which takes a single value
+// is given more than once. Slice, map and count flags, which accumulate
+// their occurrences, and func flags, whose function is called for every
+// occurrence, may always be repeated. The default is RepeatAllow.
+func (f *FlagSet) SetRepeatPolicy(policy RepeatPolicy) {
+	f.repeatPolicy = policy
+}
+
+// GetRepeatPolicy returns the policy set with SetRepeatPolicy.
+func (f *FlagSet) GetRepeatPolicy() RepeatPolicy {
+	return f.repeatPolicy
+}
+
+// accumulates reports whether every occurrence of the flag adds to its value,
+// or calls its function, rather than replacing its value.
+func (f *Flag) accumulates() bool {
+	if _, ok := f.Value.(SliceValue); ok {
+		return true
+	}
+	switch f.Value.Type() {
+	case "count", "stringToString", "stringToInt", "stringToInt64", "func", "boolfunc":
+		return true
+	}
+	return false
+}
+
+// checkRepeated applies the repeat policy to the flag read as token t.
+func (f *FlagSet) checkRepeated(t Token) error {
+	if f.repeatPolicy == RepeatAllow || t.Flag.accumulates() {
+		return nil
+	}
+	previous := f.occurrences[f.normalizeFlagName(t.Flag.Name)]
+	if len(previous) == 0 {
+		return nil
+	}
+	first := previous[len(previous)-1]
+	second := t.occurrence()
+	if f.repeatPolicy == RepeatError {
+		return &RepeatedFlagError{flag: t.Flag, first: first, second: second}
+	}
+	_, _ = fmt.Fprintln(f.Output(), f.translator.translate(MsgRepeatedFlagWarning, map[string]string{
+		"name":   t.Flag.Name,
+		"first":  first.String(),
+		"second": second.String(),
+	}))
+	return nil
+}
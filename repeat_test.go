@@ -0,0 +1,84 @@
+package pflag
+
+import (
+	"bytes"
+	"testing"
+)
+
+func setUpRepeatFlagSet(policy RepeatPolicy) (*FlagSet, *bytes.Buffer) {
+	f := NewFlagSet("test", ContinueOnError)
+	out := &bytes.Buffer{}
+	f.SetOutput(out)
+	f.SetRepeatPolicy(policy)
+	f.IntP("port", "p", 0, "port")
+	f.StringSlice("tag", nil, "tags")
+	f.CountP("verbose", "v", "verbosity")
+	f.StringToString("label", map[string]string{}, "labels")
+	f.Func("define", "definitions", func(string) error { return nil })
+	f.BoolFuncP("trace", "t", "tracing", func(string) error { return nil })
+	return f, out
+}
+
+func TestRepeatAllow(t *testing.T) {
+	f, out := setUpRepeatFlagSet(RepeatAllow)
+	if err := f.Parse([]string{"--port", "80", "-p", "8080"}); err != nil {
+		t.Fatal(err)
+	}
+	if port, _ := f.GetInt("port"); port != 8080 {
+		t.Errorf("expected the last value to win, got %d", port)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected no output, got %q", out.String())
+	}
+}
+
+func TestRepeatWarn(t *testing.T) {
+	f, out := setUpRepeatFlagSet(RepeatWarn)
+	if err := f.Parse([]string{"--port", "80", "-p", "8080"}); err != nil {
+		t.Fatal(err)
+	}
+	if port, _ := f.GetInt("port"); port != 8080 {
+		t.Errorf("expected the last value to win, got %d", port)
+	}
+	expected := "Flag --port given more than once, \"-p 8080\" overrides \"--port 80\"\n"
+	if out.String() != expected {
+		t.Errorf("expected warning %q, got %q", expected, out.String())
+	}
+}
+
+func TestRepeatError(t *testing.T) {
+	f, _ := setUpRepeatFlagSet(RepeatError)
+	err := f.Parse([]string{"--port=80", "x", "-vp8080"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	expected := "flag --port given more than once: \"--port=80\" and \"-vp8080\""
+	if err.Error() != expected {
+		t.Errorf("expected error %q, got %q", expected, err.Error())
+	}
+	repeated, ok := err.(*RepeatedFlagError) //nolint:errorlint
+	if !ok {
+		t.Fatalf("expected a *RepeatedFlagError, got %T", err)
+	}
+	if repeated.GetFlag() != f.Lookup("port") || repeated.GetFirst().Index != 0 || repeated.GetSecond().Index != 2 {
+		t.Errorf("unexpected error details: %+v %+v", repeated.GetFirst(), repeated.GetSecond())
+	}
+	if port, _ := f.GetInt("port"); port != 80 {
+		t.Errorf("expected the repeated value not to be set, got %d", port)
+	}
+}
+
+func TestRepeatAccumulatingFlags(t *testing.T) {
+	f, out := setUpRepeatFlagSet(RepeatError)
+	args := []string{"--tag=a", "--tag=b", "-vvv", "--verbose", "--label", "a=1", "--label", "b=2",
+		"--define", "x", "--define=y", "-tt"}
+	if err := f.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	if verbose, _ := f.GetCount("verbose"); verbose != 4 {
+		t.Errorf("expected verbose 4, got %d", verbose)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected no output, got %q", out.String())
+	}
+}
@@ -0,0 +1,250 @@
+package pflag
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrNoCommand is the error returned by Router.Run if no command was given.
+var ErrNoCommand = errors.New("pflag: no command given")
+
+// A Command is a subcommand dispatched by a Router.
+type Command struct {
+	Name  string                                  // name as it appears on command line
+	Short string                                  // one-line description shown in the router's help
+	Flags *FlagSet                                // flags of the command
+	Run   func(cmd *Command, args []string) error // called with the non-flag arguments
+
+	persistentAdded bool // the router's flags were merged into Flags
+	flagsCreated    bool // Flags was created by the router and shares its output
+}
+
+// A Router is a minimal dispatcher of subcommands, for tools which need
+// "tool [flags] command [command flags] [args]" without a full command
+// framework. Its flags are persistent: they are accepted before the
+// command name and are merged into every command's flags. A command flag
+// with the same name as a persistent flag hides it, while a command flag
+// with the same shorthand as a persistent flag makes Run fail with a
+// *ShorthandConflictError.
+type Router struct {
+	// Flags holds the persistent flags of the router. Parsing them stops
+	// at the first non-flag argument, which names the command.
+	Flags *FlagSet
+
+	// SuggestionsMinimumDistance is the maximum edit distance for which
+	// command names are suggested for an unknown command. Defaults to 2.
+	SuggestionsMinimumDistance int
+
+	commands map[string]*Command
+	order    []*Command
+}
+
+// NewRouter returns a new router with an empty set of commands and
+// persistent flags with the specified name and error handling property.
+func NewRouter(name string, errorHandling ErrorHandling) *Router {
+	r := &Router{
+		Flags:                      NewFlagSet(name, errorHandling),
+		SuggestionsMinimumDistance: 2,
+		commands:                   make(map[string]*Command),
+	}
+	r.Flags.SetInterspersed(false)
+	r.Flags.Usage = r.usage
+	return r
+}
+
+// AddCommand adds a command to the router. If cmd.Flags is nil a FlagSet
+// named after the router and the command is created, which writes to the
+// output of the router's flags at the time the command is run.
+func (r *Router) AddCommand(cmd *Command) {
+	if _, alreadyThere := r.commands[cmd.Name]; alreadyThere || cmd.Name == "help" {
+		msg := fmt.Sprintf("%s command redefined: %s", r.Flags.name, cmd.Name)
+		_, _ = fmt.Fprintln(r.Flags.Output(), msg)
+		panic(msg)
+	}
+	if cmd.Flags == nil {
+		cmd.Flags = NewFlagSet(r.Flags.name+" "+cmd.Name, r.Flags.errorHandling)
+		cmd.flagsCreated = true
+	}
+	r.commands[cmd.Name] = cmd
+	r.order = append(r.order, cmd)
+}
+
+// Command defines a command with the specified name, description and handler
+// and returns it, so that flags can be added to cmd.Flags.
+func (r *Router) Command(name, short string, run func(cmd *Command, args []string) error) *Command {
+	cmd := &Command{Name: name, Short: short, Run: run}
+	r.AddCommand(cmd)
+	return cmd
+}
+
+// Lookup returns the named command, returning nil if none exists.
+func (r *Router) Lookup(name string) *Command {
+	return r.commands[name]
+}
+
+// Commands returns the commands of the router in the order they were added.
+func (r *Router) Commands() []*Command {
+	return r.order
+}
+
+// Run parses the persistent flags from the argument list, which should not
+// include the command name, then parses the remaining arguments with the
+// flags of the named command and calls its handler. The built-in "help"
+// command prints the combined help of the router or of a command.
+func (r *Router) Run(arguments []string) error {
+	if err := r.Flags.Parse(arguments); err != nil {
+		return err
+	}
+
+	args := r.Flags.Args()
+	if len(args) == 0 {
+		return r.handleError(ErrNoCommand)
+	}
+
+	name := args[0]
+	if name == "help" {
+		if len(args) > 1 {
+			if cmd := r.commands[args[1]]; cmd != nil {
+				if err := r.prepare(cmd); err != nil {
+					return r.handleError(err)
+				}
+				cmd.Flags.usage()
+				return r.handleError(ErrHelp)
+			}
+		}
+		r.usage()
+		return r.handleError(ErrHelp)
+	}
+
+	cmd := r.commands[name]
+	if cmd == nil {
+		return r.handleError(&UnknownCommandError{name: name, suggestions: r.SuggestionsFor(name)})
+	}
+
+	if err := r.prepare(cmd); err != nil {
+		return r.handleError(err)
+	}
+	if err := cmd.Flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if cmd.Run == nil {
+		return nil
+	}
+	return cmd.Run(cmd, cmd.Flags.Args())
+}
+
+// prepare makes the command's flags ready to be parsed: it sets the output
+// of the flags created by the router, and merges the router's flags into the
+// command's flags, skipping those hidden by a command flag with the same
+// name. It returns the error of the first persistent flag whose shorthand is
+// used by the command.
+func (r *Router) prepare(cmd *Command) error {
+	if cmd.flagsCreated {
+		cmd.Flags.SetOutput(r.Flags.output)
+	}
+	if cmd.persistentAdded {
+		return nil
+	}
+	for _, err := range cmd.Flags.TryAddFlagSet(r.Flags) {
+		if _, hidden := err.(*FlagRedefinedError); !hidden { //nolint:errorlint
+			return err
+		}
+	}
+	cmd.persistentAdded = true
+	return nil
+}
+
+// handleError reports err according to the error handling of the router's
+// flags, as Parse does.
+func (r *Router) handleError(err error) error {
+	if err != ErrHelp { //nolint:errorlint // not using errors.Is for compatibility with go1.12
+		err = r.Flags.fail(err)
+	}
+	return r.Flags.handleError(err)
+}
+
+// SuggestionsFor returns the names of the commands which are close to the
+// given, unknown, name: those within SuggestionsMinimumDistance edits and
+// those it is a prefix of.
+func (r *Router) SuggestionsFor(name string) []string {
+	var suggestions []string
+	for _, cmd := range r.order {
+		distance := levenshteinDistance(strings.ToLower(name), strings.ToLower(cmd.Name))
+		if distance <= r.SuggestionsMinimumDistance || strings.HasPrefix(strings.ToLower(cmd.Name), strings.ToLower(name)) {
+			suggestions = append(suggestions, cmd.Name)
+		}
+	}
+	return suggestions
+}
+
+// CommandUsages returns a string containing the name and description of
+// every command of the router.
+func (r *Router) CommandUsages() string {
+	buf := new(bytes.Buffer)
+	names := make([]string, 0, len(r.order))
+	maxlen := 0
+	for _, cmd := range r.order {
+		names = append(names, cmd.Name)
+		if w := displayWidth(cmd.Name); w > maxlen {
+			maxlen = w
+		}
+	}
+	if r.Flags.SortFlags {
+		sort.Strings(names)
+	}
+	for _, name := range names {
+		cmd := r.commands[name]
+		spacing := strings.Repeat(" ", maxlen-displayWidth(name)+3)
+		_, _ = fmt.Fprintf(buf, "  %s%s%s\n", name, spacing, cmd.Short)
+	}
+	return buf.String()
+}
+
+// usage prints the combined help of the router: its synopsis, commands and
+// persistent flags.
+func (r *Router) usage() {
+	out := r.Flags.Output()
+	_, _ = fmt.Fprintln(out, r.Flags.translator.translate(MsgUsageOf, map[string]string{"name": r.Flags.name}))
+	_, _ = fmt.Fprintf(out, "  %s [flags] <command> [args]\n\n", r.Flags.name)
+	_, _ = fmt.Fprintf(out, "%s\n%s", r.Flags.translator.translate(MsgUsageCommands, nil), r.CommandUsages())
+	if r.Flags.HasAvailableFlags() {
+		_, _ = fmt.Fprintf(out, "\n%s\n", r.Flags.translator.translate(MsgUsageFlags, nil))
+		r.Flags.PrintDefaults()
+	}
+}
+
+// levenshteinDistance returns the number of single character edits needed
+// to change s into t.
+func levenshteinDistance(s, t string) int {
+	a, b := []rune(s), []rune(t)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,176 @@
+package pflag
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func setUpRouter(out *bytes.Buffer) (*Router, *bool, *string, *[]string) {
+	var verbose bool
+	var name string
+	var gotArgs []string
+	r := NewRouter("tool", ContinueOnError)
+	r.Flags.SetOutput(out)
+	r.Flags.BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+
+	add := r.Command("add", "add an item", func(_ *Command, args []string) error {
+		gotArgs = args
+		return nil
+	})
+	add.Flags.StringVarP(&name, "name", "n", "", "name of the item")
+	r.Command("remove", "remove an item", nil)
+	return r, &verbose, &name, &gotArgs
+}
+
+func TestRouterRun(t *testing.T) {
+	var out bytes.Buffer
+	r, verbose, name, args := setUpRouter(&out)
+	if err := r.Run([]string{"-v", "add", "--name", "x", "a", "-v", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if !*verbose || *name != "x" || !reflect.DeepEqual(*args, []string{"a", "b"}) {
+		t.Errorf("unexpected values: %v %q %q", *verbose, *name, *args)
+	}
+	if !r.Flags.Changed("verbose") {
+		t.Error("expected the persistent flag to be changed")
+	}
+}
+
+func TestRouterPersistentFlagAfterCommand(t *testing.T) {
+	var out bytes.Buffer
+	r, verbose, _, _ := setUpRouter(&out)
+	if err := r.Run([]string{"add", "--verbose"}); err != nil {
+		t.Fatal(err)
+	}
+	if !*verbose {
+		t.Error("expected the persistent flag to be accepted after the command name")
+	}
+}
+
+func TestRouterShorthandConflict(t *testing.T) {
+	for _, args := range [][]string{{"show"}, {"help", "show"}} {
+		var out bytes.Buffer
+		r, _, _, _ := setUpRouter(&out)
+		show := r.Command("show", "show an item", nil)
+		show.Flags.BoolP("values", "v", false, "show values")
+
+		err := r.Run(args)
+		conflict, ok := err.(*ShorthandConflictError) //nolint:errorlint
+		if !ok {
+			t.Fatalf("%q: expected a *ShorthandConflictError, got %v", args, err)
+		}
+		expected := `unable to redefine 'v' shorthand in "tool show" flagset: it's already used for "values" flag`
+		if conflict.Error() != expected {
+			t.Errorf("%q: expected %q, got %q", args, expected, conflict.Error())
+		}
+	}
+
+	var out bytes.Buffer
+	r, verbose, _, _ := setUpRouter(&out)
+	show := r.Command("show", "show an item", nil)
+	commandVerbose := show.Flags.Bool("verbose", false, "command verbose")
+	if err := r.Run([]string{"show", "--verbose"}); err != nil {
+		t.Fatal(err)
+	}
+	if !*commandVerbose || *verbose {
+		t.Error("expected the command flag to hide the persistent flag of the same name")
+	}
+}
+
+func TestRouterOutput(t *testing.T) {
+	var before, after bytes.Buffer
+	r, _, _, _ := setUpRouter(&before)
+	r.Flags.SetOutput(&after)
+	if err := r.Run([]string{"help", "add"}); err != ErrHelp { //nolint:errorlint
+		t.Fatalf("expected ErrHelp, got %v", err)
+	}
+	if before.Len() != 0 || !strings.Contains(after.String(), "--name") {
+		t.Errorf("expected the command help on the current output, got %q and %q", before.String(), after.String())
+	}
+}
+
+func TestRouterUnknownCommand(t *testing.T) {
+	var out bytes.Buffer
+	r, _, _, _ := setUpRouter(&out)
+	err := r.Run([]string{"ad"})
+	cmdErr, ok := err.(*UnknownCommandError)
+	if !ok {
+		t.Fatalf("expected an UnknownCommandError, got %v", err)
+	}
+	if cmdErr.GetSpecifiedName() != "ad" || !reflect.DeepEqual(cmdErr.GetSuggestions(), []string{"add"}) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expected := "unknown command \"ad\"\n\nDid you mean this?\n\tadd"
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	if err := r.Run([]string{"-v"}); err != ErrNoCommand { //nolint:errorlint
+		t.Errorf("expected ErrNoCommand, got %v", err)
+	}
+}
+
+func TestRouterSuggestions(t *testing.T) {
+	r := NewRouter("tool", ContinueOnError)
+	for _, name := range []string{"status", "stash", "start", "commit"} {
+		r.Command(name, "", nil)
+	}
+	if s := r.SuggestionsFor("stats"); !reflect.DeepEqual(s, []string{"status", "stash", "start"}) {
+		t.Errorf("unexpected suggestions %v", s)
+	}
+	if s := r.SuggestionsFor("com"); !reflect.DeepEqual(s, []string{"commit"}) {
+		t.Errorf("unexpected suggestions %v", s)
+	}
+	if s := r.SuggestionsFor("xyz"); len(s) != 0 {
+		t.Errorf("expected no suggestions, got %v", s)
+	}
+}
+
+func TestRouterHelp(t *testing.T) {
+	var out bytes.Buffer
+	r, _, _, _ := setUpRouter(&out)
+	if err := r.Run([]string{"help"}); err != ErrHelp { //nolint:errorlint
+		t.Fatalf("expected ErrHelp, got %v", err)
+	}
+	expected := `Usage of tool:
+  tool [flags] <command> [args]
+
+Commands:
+  add      add an item
+  remove   remove an item
+
+Flags:
+  -v, --verbose[=true|false]   verbose output
+`
+	if out.String() != expected {
+		t.Errorf("Expected \n%s \nActual \n%s", expected, out.String())
+	}
+
+	out.Reset()
+	if err := r.Run([]string{"help", "add"}); err != ErrHelp { //nolint:errorlint
+		t.Fatalf("expected ErrHelp, got %v", err)
+	}
+	if s := out.String(); !strings.HasPrefix(s, "Usage of tool add:") || !strings.Contains(s, "--name") || !strings.Contains(s, "--verbose") {
+		t.Errorf("expected the command help with persistent flags, got %q", s)
+	}
+}
+
+func TestLevenshteinDistance(t *testing.T) {
+	tests := []struct {
+		s, t     string
+		distance int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"add", "ad", 1},
+		{"héllo", "hello", 1},
+	}
+	for _, test := range tests {
+		if d := levenshteinDistance(test.s, test.t); d != test.distance {
+			t.Errorf("levenshteinDistance(%q, %q): expected %d, got %d", test.s, test.t, test.distance, d)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package pflag
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SchemaRequiredAnnotation is the flag annotation which marks a flag as
+	// required in the schema generated by JSONSchema. Any value other than
+	// "false" counts as required.
+	SchemaRequiredAnnotation = "pflag_schema_required"
+	// SchemaEnumAnnotation is the flag annotation which lists the values
+	// allowed for a flag in the schema generated by JSONSchema.
+	SchemaEnumAnnotation = "pflag_schema_enum"
+
+	// cobraRequiredAnnotation is the annotation set by cobra's
+	// MarkFlagRequired, which is honored as well.
+	cobraRequiredAnnotation = "cobra_annotation_bash_completion_one_required_flag"
+
+	jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+	durationPattern = `^[-+]?(0|([0-9]*(\.[0-9]*)?(ns|us|µs|μs|ms|s|m|h))+)$`
+)
+
+// TypeJSONSchema returns the JSON Schema fragment describing values of the
+// given flag type, as reported by Value.Type(). Types that are not known to
+// pflag are described as plain strings, since that is how every flag value
+// is written on the command line.
+func TypeJSONSchema(typeName string) map[string]interface{} {
+	switch typeName {
+	case "bool", "boolfunc":
+		return map[string]interface{}{"type": "boolean"}
+	case "count":
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case "int", "int64":
+		return map[string]interface{}{"type": "integer", "minimum": int64(math.MinInt64), "maximum": int64(math.MaxInt64)}
+	case "int8":
+		return map[string]interface{}{"type": "integer", "minimum": math.MinInt8, "maximum": math.MaxInt8}
+	case "int16":
+		return map[string]interface{}{"type": "integer", "minimum": math.MinInt16, "maximum": math.MaxInt16}
+	case "int32":
+		return map[string]interface{}{"type": "integer", "minimum": math.MinInt32, "maximum": math.MaxInt32}
+	case "uint", "uint64":
+		return map[string]interface{}{"type": "integer", "minimum": 0, "maximum": uint64(math.MaxUint64)}
+	case "uint8":
+		return map[string]interface{}{"type": "integer", "minimum": 0, "maximum": math.MaxUint8}
+	case "uint16":
+		return map[string]interface{}{"type": "integer", "minimum": 0, "maximum": math.MaxUint16}
+	case "uint32":
+		return map[string]interface{}{"type": "integer", "minimum": 0, "maximum": uint32(math.MaxUint32)}
+	case "float32", "float64":
+		return map[string]interface{}{"type": "number"}
+	case "duration":
+		return map[string]interface{}{"type": "string", "pattern": durationPattern}
+	case "ip":
+		return map[string]interface{}{
+			"type":  "string",
+			"anyOf": []interface{}{map[string]interface{}{"format": "ipv4"}, map[string]interface{}{"format": "ipv6"}},
+		}
+	case "ipMask":
+		return map[string]interface{}{"type": "string"}
+	case "ipNet":
+		return map[string]interface{}{"type": "string", "pattern": `^[0-9A-Fa-f:.]+/[0-9]+$`}
+	case "bytesHex":
+		return map[string]interface{}{"type": "string", "pattern": `^([0-9A-Fa-f]{2})*$`}
+	case "bytesBase64":
+		return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+	case "stringArray":
+		return map[string]interface{}{"type": "array", "items": TypeJSONSchema("string")}
+	case "stringToString":
+		return map[string]interface{}{"type": "object", "additionalProperties": TypeJSONSchema("string")}
+	case "stringToInt":
+		return map[string]interface{}{"type": "object", "additionalProperties": TypeJSONSchema("int")}
+	case "stringToInt64":
+		return map[string]interface{}{"type": "object", "additionalProperties": TypeJSONSchema("int64")}
+	}
+	if strings.HasSuffix(typeName, "Slice") {
+		return map[string]interface{}{"type": "array", "items": TypeJSONSchema(strings.TrimSuffix(typeName, "Slice"))}
+	}
+	return map[string]interface{}{"type": "string"}
+}
+
+// JSONSchema returns a JSON Schema document describing a configuration
+// object whose properties are the flags of the FlagSet. Each property
+// carries the flag's usage as description, its default value, the values
+// listed in the SchemaEnumAnnotation annotation and whether it is
+// deprecated. Flags annotated with SchemaRequiredAnnotation are listed as
+// required.
+func (f *FlagSet) JSONSchema() ([]byte, error) {
+	properties := make(map[string]interface{}, len(f.formal))
+	required := []string{}
+	f.VisitAll(func(flag *Flag) {
+		properties[flag.Name] = flag.jsonSchema()
+		if flag.isSchemaRequired() {
+			required = append(required, flag.Name)
+		}
+	})
+
+	schema := map[string]interface{}{
+		"$schema":              jsonSchemaDraft,
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if f.name != "" {
+		schema["title"] = f.name
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// JSONSchema returns a JSON Schema document describing the command-line flags.
+func JSONSchema() ([]byte, error) {
+	return CommandLine.JSONSchema()
+}
+
+// jsonSchema returns the schema fragment for a single flag.
+func (f *Flag) jsonSchema() map[string]interface{} {
+	typeName := f.Value.Type()
+	schema := TypeJSONSchema(typeName)
+	if tv, ok := f.Value.(*timeValue); ok {
+		for _, format := range tv.formats {
+			if format == time.RFC3339 || format == time.RFC3339Nano {
+				schema["format"] = "date-time"
+				break
+			}
+		}
+	}
+
+	_, usage := UnquoteUsage(f)
+	if usage != "" {
+		schema["description"] = usage
+	}
+	if f.Deprecated != "" {
+		schema["deprecated"] = true
+	}
+	if def, ok := jsonSchemaValue(typeName, f.DefValue); ok {
+		schema["default"] = def
+	}
+	if values, ok := f.Annotations[SchemaEnumAnnotation]; ok {
+		enum := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			if ev, ok := jsonSchemaValue(typeName, v); ok {
+				enum = append(enum, ev)
+			}
+		}
+		schema["enum"] = enum
+	}
+	return schema
+}
+
+// isSchemaRequired returns true if the flag is annotated as required,
+// either by SchemaRequiredAnnotation or by cobra's MarkFlagRequired.
+func (f *Flag) isSchemaRequired() bool {
+	for _, key := range []string{SchemaRequiredAnnotation, cobraRequiredAnnotation} {
+		values, ok := f.Annotations[key]
+		if !ok {
+			continue
+		}
+		if len(values) == 0 || values[0] != "false" {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonSchemaValue converts the textual form of a flag value, as produced by
+// Value.String(), into the JSON value matching TypeJSONSchema(typeName). It
+// returns false if s is not a valid value for the type.
+func jsonSchemaValue(typeName, s string) (interface{}, bool) {
+	switch typeName {
+	case "bool", "boolfunc":
+		b, err := strconv.ParseBool(s)
+		return b, err == nil
+	case "count", "int", "int8", "int16", "int32", "int64":
+		i, err := strconv.ParseInt(s, 0, 64)
+		return i, err == nil
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		u, err := strconv.ParseUint(s, 0, 64)
+		return u, err == nil
+	case "float32", "float64":
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsInf(v, 0) || math.IsNaN(v) {
+			return nil, false
+		}
+		return v, true
+	case "ip", "ipMask", "ipNet", "time":
+		// These print "<nil>" or "" for their zero value, which is not a
+		// valid value for the schema.
+		if s == "" || s == "<nil>" {
+			return nil, false
+		}
+		return s, true
+	case "stringToString", "stringToInt", "stringToInt64":
+		pairs, ok := jsonSchemaList(s)
+		if !ok {
+			return nil, false
+		}
+		elemType := TypeJSONSchema(typeName)["additionalProperties"].(map[string]interface{})["type"]
+		out := make(map[string]interface{}, len(pairs))
+		for _, pair := range pairs {
+			kv := strings.SplitN(pair, "=", 2)
+			if len(kv) != 2 {
+				return nil, false
+			}
+			if elemType == "string" {
+				out[kv[0]] = kv[1]
+				continue
+			}
+			v, ok := jsonSchemaValue("int64", kv[1])
+			if !ok {
+				return nil, false
+			}
+			out[kv[0]] = v
+		}
+		return out, true
+	}
+	if typeName == "stringArray" || strings.HasSuffix(typeName, "Slice") {
+		elemType := strings.TrimSuffix(typeName, "Slice")
+		if typeName == "stringArray" {
+			elemType = "string"
+		}
+		items, ok := jsonSchemaList(s)
+		if !ok {
+			return nil, false
+		}
+		out := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			v, ok := jsonSchemaValue(elemType, item)
+			if !ok {
+				return nil, false
+			}
+			out = append(out, v)
+		}
+		return out, true
+	}
+	return s, true
+}
+
+// jsonSchemaList splits the "[a,b,c]" form printed by slice and map values.
+func jsonSchemaList(s string) ([]string, bool) {
+	if len(s) < 2 || s[0] != '[' || s[len(s)-1] != ']' {
+		return nil, false
+	}
+	items, err := readAsCSV(s[1 : len(s)-1])
+	return items, err == nil
+}
@@ -0,0 +1,129 @@
+package pflag
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTypeJSONSchema(t *testing.T) {
+	tests := []struct {
+		typeName string
+		expected string
+	}{
+		{"bool", "boolean"},
+		{"int32", "integer"},
+		{"float64", "number"},
+		{"duration", "string"},
+		{"stringSlice", "array"},
+		{"ipNetSlice", "array"},
+		{"stringArray", "array"},
+		{"stringToInt64", "object"},
+		{"custom", "string"},
+	}
+	for _, test := range tests {
+		if got := TypeJSONSchema(test.typeName)["type"]; got != test.expected {
+			t.Errorf("TypeJSONSchema(%q) type: expected %q, got %q", test.typeName, test.expected, got)
+		}
+	}
+
+	items := TypeJSONSchema("int32Slice")["items"].(map[string]interface{})
+	if items["type"] != "integer" || items["maximum"] != 2147483647 {
+		t.Errorf("unexpected items schema for int32Slice: %v", items)
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.Int32("port", 8080, "listen `port`")
+	f.StringSlice("tags", []string{"a", "b"}, "tags to apply")
+	f.StringToInt64("limits", map[string]int64{"cpu": 2}, "resource limits")
+	f.IPNet("subnet", net.IPNet{}, "subnet to use")
+	f.Duration("timeout", 5*time.Second, "request timeout")
+	f.Time("start", time.Time{}, []string{time.RFC3339}, "start time")
+	f.String("mode", "fast", "mode of operation")
+	f.Bool("old", false, "old flag")
+	_ = f.SetAnnotation("mode", SchemaEnumAnnotation, []string{"fast", "slow"})
+	_ = f.SetAnnotation("port", SchemaRequiredAnnotation, []string{"true"})
+	_ = f.MarkDeprecated("old", "use something else")
+
+	out, err := f.JSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(out, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	if schema["$schema"] != jsonSchemaDraft || schema["type"] != "object" || schema["title"] != "test" {
+		t.Errorf("unexpected schema header: %v", schema)
+	}
+	if !reflect.DeepEqual(schema["required"], []interface{}{"port"}) {
+		t.Errorf("expected required [port], got %v", schema["required"])
+	}
+
+	props := schema["properties"].(map[string]interface{})
+	if len(props) != 8 {
+		t.Errorf("expected 8 properties, got %d", len(props))
+	}
+
+	port := props["port"].(map[string]interface{})
+	if port["type"] != "integer" || port["default"] != float64(8080) || port["description"] != "listen port" {
+		t.Errorf("unexpected port schema: %v", port)
+	}
+
+	tags := props["tags"].(map[string]interface{})
+	if !reflect.DeepEqual(tags["default"], []interface{}{"a", "b"}) {
+		t.Errorf("unexpected tags default: %v", tags["default"])
+	}
+
+	limits := props["limits"].(map[string]interface{})
+	if !reflect.DeepEqual(limits["default"], map[string]interface{}{"cpu": float64(2)}) {
+		t.Errorf("unexpected limits default: %v", limits["default"])
+	}
+
+	if _, ok := props["subnet"].(map[string]interface{})["default"]; ok {
+		t.Errorf("expected no default for an empty ipNet")
+	}
+
+	timeout := props["timeout"].(map[string]interface{})
+	if timeout["default"] != "5s" || timeout["pattern"] != durationPattern {
+		t.Errorf("unexpected timeout schema: %v", timeout)
+	}
+
+	if props["start"].(map[string]interface{})["format"] != "date-time" {
+		t.Errorf("expected date-time format for an RFC3339 time flag")
+	}
+
+	mode := props["mode"].(map[string]interface{})
+	if !reflect.DeepEqual(mode["enum"], []interface{}{"fast", "slow"}) {
+		t.Errorf("unexpected mode enum: %v", mode["enum"])
+	}
+
+	if props["old"].(map[string]interface{})["deprecated"] != true {
+		t.Errorf("expected old to be deprecated")
+	}
+}
+
+func TestJSONSchemaCobraRequired(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.String("name", "", "name")
+	f.String("other", "", "other")
+	_ = f.SetAnnotation("name", cobraRequiredAnnotation, []string{"true"})
+	_ = f.SetAnnotation("other", SchemaRequiredAnnotation, []string{"false"})
+
+	out, err := f.JSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(out, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(schema["required"], []interface{}{"name"}) {
+		t.Errorf("expected required [name], got %v", schema["required"])
+	}
+}
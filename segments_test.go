@@ -0,0 +1,77 @@
+package pflag
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestArgsSegments(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected [][]string
+	}{
+		{[]string{"a", "--opt", "b"}, [][]string{{"a", "b"}}},
+		{[]string{"--opt", "--"}, [][]string{{}, {}}},
+		{[]string{"a", "--opt", "--", "child1", "-x", "--", "child2", "--", "--"},
+			[][]string{{"a"}, {"child1", "-x"}, {"child2"}, {}, {}}},
+	}
+	for _, test := range tests {
+		f := NewFlagSet("test", ContinueOnError)
+		f.Bool("opt", false, "opt")
+		if err := f.Parse(test.args); err != nil {
+			t.Fatal(err)
+		}
+		if got := f.ArgsSegments(); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%q: expected %q, got %q", test.args, test.expected, got)
+		}
+	}
+}
+
+func TestArgsSegmentsNotInterspersed(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected [][]string
+	}{
+		{[]string{"--opt", "cmd", "a", "-- ", "--opt"}, [][]string{{"cmd", "a", "-- ", "--opt"}}},
+		{[]string{"--opt", "cmd", "a", "--", "b", "--", "c"}, [][]string{{"cmd", "a"}, {"b"}, {"c"}}},
+		{[]string{"--opt", "--", "cmd", "--", "b"}, [][]string{{}, {"cmd"}, {"b"}}},
+	}
+	for _, test := range tests {
+		f := NewFlagSet("test", ContinueOnError)
+		f.SetInterspersed(false)
+		f.Bool("opt", false, "opt")
+		if err := f.Parse(test.args); err != nil {
+			t.Fatal(err)
+		}
+		if got := f.ArgsSegments(); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%q: expected %q, got %q", test.args, test.expected, got)
+		}
+	}
+}
+
+func TestPassUnknownFlagToArgsInPlace(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	f.ParseErrorsAllowlist.UnknownFlagsHandling = PassUnknownFlagToArgsInPlace
+	f.BoolP("first", "f", false, "first")
+	f.BoolP("ith", "i", false, "ith")
+	f.StringP("name", "n", "", "name")
+
+	args := []string{"-fgkij", "a", "--unknown=x", "-xnvalue", "-gnvalue", "-f", "--", "-g"}
+	if err := f.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"-gk", "-j", "a", "--unknown=x", "-x", "-g", "-g"}
+	if !reflect.DeepEqual(f.Args(), expected) {
+		t.Errorf("expected %q, got %q", expected, f.Args())
+	}
+
+	f.ParseErrorsAllowlist.UnknownFlagsHandling = PassUnknownFlagToArgs
+	if err := f.Parse([]string{"-fgkij"}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(f.Args(), []string{"-gkj"}) {
+		t.Errorf("expected the unknown shorthands to be combined, got %q", f.Args())
+	}
+}
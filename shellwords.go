@@ -0,0 +1,120 @@
+package pflag
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// SplitCommandLine splits a command line into arguments following the
+// quoting rules of the POSIX shell: words are separated by unquoted blanks
+// and newlines, single quotes preserve every character up to the closing
+// quote, double quotes preserve every character but a backslash escaping
+// one of $ ` " \ or a newline, and an unquoted backslash preserves the next
+// character. A backslash followed by a newline is removed. No expansion
+// (variables, globs, tildes, command substitution) or comment handling is
+// performed.
+func SplitCommandLine(s string) ([]string, error) {
+	var args []string
+	var word bytes.Buffer
+	inWord := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case ' ', '\t', '\n':
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case '\\':
+			if i+1 >= len(s) {
+				return nil, fmt.Errorf("trailing backslash in command line %q", s)
+			}
+			i++
+			if s[i] == '\n' {
+				continue
+			}
+			word.WriteByte(s[i])
+			inWord = true
+		case '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote in command line %q", s)
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					switch s[i+1] {
+					case '$', '`', '"', '\\':
+						i++
+					case '\n':
+						i++
+						continue
+					}
+				}
+				word.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, fmt.Errorf("unterminated double quote in command line %q", s)
+			}
+			inWord = true
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		args = append(args, word.String())
+	}
+	return args, nil
+}
+
+// shellSafe reports whether c never needs quoting in a POSIX shell word.
+func shellSafe(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("@%+=:,./-_", c) >= 0 || c >= 0x80
+}
+
+// QuoteArg quotes an argument so that SplitCommandLine, or a POSIX shell,
+// reads it back as a single word with the same value. Arguments which need
+// no quoting are returned unchanged.
+func QuoteArg(s string) string {
+	if s == "" {
+		return "''"
+	}
+	for i := 0; i < len(s); i++ {
+		if !shellSafe(s[i]) {
+			return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+		}
+	}
+	return s
+}
+
+// QuoteCommandLine is the inverse of SplitCommandLine: it quotes every
+// argument with QuoteArg and joins them with spaces.
+func QuoteCommandLine(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = QuoteArg(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// ParseString splits cmdline with SplitCommandLine and parses the resulting
+// arguments, which should not include the command name, as Parse does.
+func (f *FlagSet) ParseString(cmdline string) error {
+	args, err := SplitCommandLine(cmdline)
+	if err != nil {
+		return f.handleError(f.fail(err))
+	}
+	return f.Parse(args)
+}
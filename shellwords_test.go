@@ -0,0 +1,81 @@
+package pflag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected []string
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"a b\tc\nd", []string{"a", "b", "c", "d"}},
+		{`--name 'hello world'`, []string{"--name", "hello world"}},
+		{`--name="hello world"`, []string{"--name=hello world"}},
+		{`'it'\''s'`, []string{"it's"}},
+		{`"a \"quoted\" \$word \\ \x"`, []string{`a "quoted" $word \ \x`}},
+		{`a\ b c\\d`, []string{"a b", `c\d`}},
+		{"line\\\ncontinued", []string{"linecontinued"}},
+		{`'' ""`, []string{"", ""}},
+		{`$HOME ~ *.go`, []string{"$HOME", "~", "*.go"}},
+		{`'single $x "y"'`, []string{`single $x "y"`}},
+		{`x"y"'z'`, []string{"xyz"}},
+	}
+	for _, test := range tests {
+		got, err := SplitCommandLine(test.in)
+		if err != nil {
+			t.Errorf("SplitCommandLine(%q): unexpected error %v", test.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("SplitCommandLine(%q): expected %q, got %q", test.in, test.expected, got)
+		}
+	}
+}
+
+func TestSplitCommandLineErrors(t *testing.T) {
+	for _, in := range []string{`'open`, `"open`, `trailing\`, `"escaped\"`} {
+		if _, err := SplitCommandLine(in); err == nil {
+			t.Errorf("SplitCommandLine(%q): expected an error", in)
+		}
+	}
+}
+
+func TestQuoteCommandLine(t *testing.T) {
+	args := []string{"plain", "", "with space", "it's", `"double"`, "$HOME", "a\nb", "--flag=x,y", "日本"}
+	quoted := QuoteCommandLine(args)
+	expected := `plain '' 'with space' 'it'\''s' '"double"' '$HOME' 'a` + "\n" + `b' --flag=x,y 日本`
+	if quoted != expected {
+		t.Errorf("expected %q, got %q", expected, quoted)
+	}
+
+	got, err := SplitCommandLine(quoted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, args) {
+		t.Errorf("round trip: expected %q, got %q", args, got)
+	}
+}
+
+func TestParseString(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	name := f.String("name", "", "name")
+	count := f.IntP("count", "c", 0, "count")
+	if err := f.ParseString(`--name "hello world" -c 3 'arg one' two`); err != nil {
+		t.Fatal(err)
+	}
+	if *name != "hello world" || *count != 3 {
+		t.Errorf("unexpected values: %q %d", *name, *count)
+	}
+	if !reflect.DeepEqual(f.Args(), []string{"arg one", "two"}) {
+		t.Errorf("unexpected args %q", f.Args())
+	}
+
+	if err := f.ParseString(`--name 'unterminated`); err == nil {
+		t.Error("expected an error for an unterminated quote")
+	}
+}
@@ -0,0 +1,29 @@
+package pflag
+
+import "unicode/utf8"
+
+// SetMultiCharShorthands sets whether shorthands may be more than one
+// character, for tools following conventions such as "find -name" or
+// "java -Xmx512m". It must be called before such flags are defined. An
+// argument starting with a single dash is read from left to right, taking
+// at each step the longest defined shorthand, so "-cpv" is "-cp -v" if "cp"
+// is defined, and "-c -p -v" otherwise.
+func (f *FlagSet) SetMultiCharShorthands(enabled bool) {
+	f.multiShorthands = enabled
+}
+
+// nextShorthand returns the shorthand at the start of a series of
+// shorthands: the longest defined shorthand it starts with, or its first
+// character if none is defined.
+func (f *FlagSet) nextShorthand(shorthands string) string {
+	_, size := utf8.DecodeRuneInString(shorthands)
+	for n := f.maxShorthandLen; n > size; n-- {
+		if n > len(shorthands) {
+			continue
+		}
+		if _, exists := f.shorthands[shorthands[:n]]; exists {
+			return shorthands[:n]
+		}
+	}
+	return shorthands[:size]
+}
@@ -0,0 +1,103 @@
+package pflag
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestUnicodeShorthand(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	e := f.BoolP("etendu", "é", false, "étendu")
+	n := f.IntP("nombre", "ñ", 0, "nombre")
+	if err := f.Parse([]string{"-éñ3", "-ñ=4", "-ñ", "5"}); err != nil {
+		t.Fatal(err)
+	}
+	if !*e || *n != 5 {
+		t.Errorf("unexpected values: %v %d", *e, *n)
+	}
+	if f.ShorthandLookup("é") == nil {
+		t.Error("expected to look up a non-ASCII shorthand")
+	}
+	if len(f.Occurrences("nombre")) != 3 {
+		t.Errorf("expected three occurrences, got %+v", f.Occurrences("nombre"))
+	}
+
+	err := f.Parse([]string{"-éü"})
+	if err == nil || err.Error() != "unknown shorthand flag: 'ü' in -ü" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestMultiCharShorthandsDisabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		define   func(f *FlagSet)
+		expected string
+	}{
+		{
+			name:     "multi-character shorthand",
+			define:   func(f *FlagSet) { f.StringP("classpath", "cp", "", "class path") },
+			expected: `"cp" shorthand is more than one ASCII character`,
+		},
+		{
+			name: "shorthand conflict",
+			define: func(f *FlagSet) {
+				f.BoolP("verbose", "v", false, "verbose")
+				f.BoolP("version", "v", false, "version")
+			},
+			expected: `unable to redefine 'v' shorthand in "test" flagset: it's already used for "verbose" flag`,
+		},
+		{
+			name:     "multi-character lookup",
+			define:   func(f *FlagSet) { f.ShorthandLookup("cp") },
+			expected: `can not look up shorthand which is more than one ASCII character: "cp"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := NewFlagSet("test", ContinueOnError)
+			out := &bytes.Buffer{}
+			f.SetOutput(out)
+			defer func() {
+				if r := recover(); r != test.expected {
+					t.Errorf("expected panic %q, got %v", test.expected, r)
+				}
+				if out.String() != test.expected {
+					t.Errorf("expected output %q, got %q", test.expected, out.String())
+				}
+			}()
+			test.define(f)
+		})
+	}
+}
+
+func TestMultiCharShorthands(t *testing.T) {
+	f := NewFlagSet("java", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	f.SetMultiCharShorthands(true)
+	cp := f.StringP("classpath", "cp", "", "class path")
+	xmx := f.StringP("max-heap", "Xmx", "", "maximum heap size")
+	c := f.BoolP("compile", "c", false, "compile")
+	p := f.BoolP("print", "p", false, "print")
+	v := f.BoolP("verbose", "v", false, "verbose")
+
+	if err := f.Parse([]string{"-cp", "lib.jar", "-Xmx512m", "-cv", "Main"}); err != nil {
+		t.Fatal(err)
+	}
+	if *cp != "lib.jar" || *xmx != "512m" || !*c || !*v || *p {
+		t.Errorf("unexpected values: %q %q %v %v %v", *cp, *xmx, *c, *v, *p)
+	}
+	if !reflect.DeepEqual(f.Args(), []string{"Main"}) {
+		t.Errorf("unexpected args: %q", f.Args())
+	}
+	if f.ShorthandLookup("Xmx") != f.Lookup("max-heap") {
+		t.Error("expected to look up a multi-character shorthand")
+	}
+
+	err := f.Parse([]string{"-vcp"})
+	if err == nil || err.Error() != `flag needs an argument: "cp" in -cp` {
+		t.Errorf("expected the longest shorthand to be matched, got %v", err)
+	}
+}
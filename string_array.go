@@ -45,6 +45,11 @@ func (s *stringArrayValue) Type() string {
 	return "stringArray"
 }
 
+// clearChanged makes the next Set replace the value instead of adding to it.
+func (s *stringArrayValue) clearChanged() {
+	s.changed = false
+}
+
 func (s *stringArrayValue) String() string {
 	str, _ := writeAsCSV(*s.value)
 	return "[" + str + "]"
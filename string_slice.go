@@ -57,6 +57,11 @@ func (s *stringSliceValue) Type() string {
 	return "stringSlice"
 }
 
+// clearChanged makes the next Set replace the value instead of adding to it.
+func (s *stringSliceValue) clearChanged() {
+	s.changed = false
+}
+
 func (s *stringSliceValue) String() string {
 	str, _ := writeAsCSV(*s.value)
 	return "[" + str + "]"
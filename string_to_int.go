@@ -50,6 +50,11 @@ func (s *stringToIntValue) Type() string {
 	return "stringToInt"
 }
 
+// clearChanged makes the next Set replace the value instead of adding to it.
+func (s *stringToIntValue) clearChanged() {
+	s.changed = false
+}
+
 func (s *stringToIntValue) String() string {
 	var buf bytes.Buffer
 	i := 0
@@ -50,6 +50,11 @@ func (s *stringToInt64Value) Type() string {
 	return "stringToInt64"
 }
 
+// clearChanged makes the next Set replace the value instead of adding to it.
+func (s *stringToInt64Value) clearChanged() {
+	s.changed = false
+}
+
 func (s *stringToInt64Value) String() string {
 	var buf bytes.Buffer
 	i := 0
@@ -66,6 +66,11 @@ func (s *stringToStringValue) Type() string {
 	return "stringToString"
 }
 
+// clearChanged makes the next Set replace the value instead of adding to it.
+func (s *stringToStringValue) clearChanged() {
+	s.changed = false
+}
+
 func (s *stringToStringValue) String() string {
 	keys := make([]string, 0, len(*s.value))
 	for k := range *s.value {
@@ -0,0 +1,103 @@
+package pflag
+
+import "strings"
+
+// Syntax is a set of flag syntaxes accepted on the command line in addition
+// to the GNU syntax of --name and -n shorthands. The same flag definitions
+// are used whatever the syntax.
+type Syntax int
+
+const (
+	// GNUSyntax accepts only --name and -n shorthand flags. It is the default.
+	GNUSyntax Syntax = 0
+	// SingleDashLongSyntax accepts long flags with a single dash, as the flag
+	// package of the standard library does: -name, -name=value and
+	// -name value. An argument whose name, up to an '=', is a defined long
+	// flag is read as that flag, otherwise it is read as shorthands.
+	SingleDashLongSyntax Syntax = 1 << (iota - 1)
+	// SlashSyntax accepts DOS-style flags: /name, /name:value, /name=value
+	// and /name value, where name is a long flag or a shorthand. An argument
+	// which does not name a defined flag, such as an absolute path, is a
+	// non-flag argument. "/?" requests help unless a flag named "?" exists.
+	SlashSyntax
+)
+
+// SetSyntax sets the flag syntaxes accepted by Parse in addition to the GNU
+// syntax, e.g. SingleDashLongSyntax|SlashSyntax.
+func (f *FlagSet) SetSyntax(syntax Syntax) {
+	f.syntax = syntax
+}
+
+// GetSyntax returns the syntaxes set with SetSyntax.
+func (f *FlagSet) GetSyntax() Syntax {
+	return f.syntax
+}
+
+// isSingleDashLongFlag reports whether the argument s, which starts with a
+// single dash, is read as a long flag.
+func (f *FlagSet) isSingleDashLongFlag(s string) bool {
+	if f.syntax&SingleDashLongSyntax == 0 {
+		return false
+	}
+	name := s[1:]
+	if i := strings.IndexByte(name, '='); i >= 0 {
+		name = name[:i]
+	}
+	_, exists := f.formal[f.normalizeFlagName(name)]
+	return exists
+}
+
+// splitSlashArg splits the argument "/name:value" into its name and, if
+// present, its value.
+func splitSlashArg(s string) (name, value string, hasValue bool) {
+	name = s[1:]
+	if i := strings.IndexAny(name, ":="); i >= 0 {
+		return name[:i], name[i+1:], true
+	}
+	return name, "", false
+}
+
+// lookupSlashName returns the flag named by name in an argument using the
+// slash syntax, trying long names before shorthands.
+func (f *FlagSet) lookupSlashName(name string) (flag *Flag, shorthand bool) {
+	if flag, exists := f.formal[f.normalizeFlagName(name)]; exists {
+		return flag, false
+	}
+	if flag, exists := f.shorthands[name]; exists {
+		return flag, true
+	}
+	return nil, false
+}
+
+// isSlashFlag reports whether the argument s is read as a flag using the
+// slash syntax.
+func (f *FlagSet) isSlashFlag(s string) bool {
+	if f.syntax&SlashSyntax == 0 || len(s) < 2 || s[0] != '/' {
+		return false
+	}
+	name, _, _ := splitSlashArg(s)
+	flag, _ := f.lookupSlashName(name)
+	return flag != nil || name == "?"
+}
+
+func (tk *tokenizer) parseSlashArg(s string, args []string) (a []string, err error) {
+	name, value, hasValue := splitSlashArg(s)
+	flag, shorthand := tk.f.lookupSlashName(name)
+	if flag == nil {
+		// "/?" with no flag named "?"
+		return args, ErrHelp
+	}
+	if shorthand && hasValue && value == "" {
+		// "/n:" sets an empty value, where "-n=" would set "="
+		tk.emit(Token{Kind: ShortFlagToken, Index: tk.index, Arg: tk.arg, Name: name, Flag: flag, ValueOrigin: InlineValue, ValueIndex: tk.index})
+		return args, nil
+	}
+	if hasValue {
+		name += "=" + value
+	}
+	if !shorthand {
+		return tk.parseLongName(s, name, args)
+	}
+	_, a, err = tk.parseSingleShortArg(name, args)
+	return a, err
+}
@@ -0,0 +1,118 @@
+package pflag
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func setUpSyntaxFlagSet(syntax Syntax) *FlagSet {
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	f.SetSyntax(syntax)
+	f.BoolP("all", "a", false, "all")
+	f.BoolP("brief", "b", false, "brief")
+	f.IntP("count", "c", 0, "count")
+	f.String("output", "", "output")
+	f.String("ab", "", "long flag spelled like a shorthand cluster")
+	return f
+}
+
+func TestSingleDashLongSyntax(t *testing.T) {
+	f := setUpSyntaxFlagSet(SingleDashLongSyntax)
+	args := []string{"-output", "out.txt", "-count=3", "-ab=x", "-ba", "arg"}
+	if err := f.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	output, _ := f.GetString("output")
+	count, _ := f.GetInt("count")
+	ab, _ := f.GetString("ab")
+	all, _ := f.GetBool("all")
+	brief, _ := f.GetBool("brief")
+	if output != "out.txt" || count != 3 || ab != "x" || !all || !brief {
+		t.Errorf("unexpected values: %q %d %q %v %v", output, count, ab, all, brief)
+	}
+	if !reflect.DeepEqual(f.Args(), []string{"arg"}) {
+		t.Errorf("unexpected args: %q", f.Args())
+	}
+}
+
+func TestSingleDashLongSyntaxDisabled(t *testing.T) {
+	f := setUpSyntaxFlagSet(GNUSyntax)
+	if err := f.Parse([]string{"-output"}); err == nil {
+		t.Error("expected -output to be read as shorthands by default")
+	}
+}
+
+func TestSlashSyntax(t *testing.T) {
+	f := setUpSyntaxFlagSet(SlashSyntax)
+	args := []string{"/output:C:\\out.txt", "/count", "3", "/a", "/usr/bin/env", "/b=false", "/"}
+	if err := f.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	output, _ := f.GetString("output")
+	count, _ := f.GetInt("count")
+	all, _ := f.GetBool("all")
+	if output != "C:\\out.txt" || count != 3 || !all || f.Changed("ab") {
+		t.Errorf("unexpected values: %q %d %v", output, count, all)
+	}
+	if !f.Changed("brief") {
+		t.Error("expected /b=false to set brief")
+	}
+	if !reflect.DeepEqual(f.Args(), []string{"/usr/bin/env", "/"}) {
+		t.Errorf("expected paths to be non-flag arguments, got %q", f.Args())
+	}
+}
+
+func TestSlashSyntaxEmptyValue(t *testing.T) {
+	tests := []struct {
+		arg   string
+		value string
+	}{
+		{"/n:", ""},
+		{"/name:", ""},
+		{"/n=", ""},
+		{"/n:=", "="},
+	}
+	for _, test := range tests {
+		f := setUpSyntaxFlagSet(SlashSyntax)
+		f.StringP("name", "n", "default", "name")
+		if err := f.Parse([]string{test.arg}); err != nil {
+			t.Fatalf("%s: %v", test.arg, err)
+		}
+		if name, _ := f.GetString("name"); name != test.value || !f.Changed("name") {
+			t.Errorf("%s: expected %q, got %q", test.arg, test.value, name)
+		}
+	}
+}
+
+func TestSlashSyntaxTokens(t *testing.T) {
+	f := setUpSyntaxFlagSet(SlashSyntax | SingleDashLongSyntax)
+	tokens, err := f.Tokenize([]string{"/c:2", "-output", "x", "/tmp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []tokenSummary{
+		{ShortFlagToken, 0, "c", "2", InlineValue, 0},
+		{LongFlagToken, 1, "output", "x", NextArgValue, 2},
+		{PositionalToken, 3, "", "/tmp", NoValue, 3},
+	}
+	if got := summarizeTokens(tokens); !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected tokens:\n got %+v\nwant %+v", got, expected)
+	}
+	if tokens[0].Arg != "/c:2" {
+		t.Errorf("expected the original argument, got %q", tokens[0].Arg)
+	}
+}
+
+func TestSlashSyntaxHelp(t *testing.T) {
+	f := setUpSyntaxFlagSet(SlashSyntax)
+	if err := f.Parse([]string{"/?"}); err != ErrHelp { //nolint:errorlint
+		t.Errorf("expected ErrHelp, got %v", err)
+	}
+
+	f = setUpSyntaxFlagSet(GNUSyntax)
+	if err := f.Parse([]string{"/?"}); err != nil || f.Arg(0) != "/?" {
+		t.Errorf("expected /? to be an argument by default, got %v %q", err, f.Args())
+	}
+}
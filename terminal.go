@@ -0,0 +1,50 @@
+package pflag
+
+import (
+	"io"
+	"os"
+	"strconv"
+)
+
+// TerminalWidth can be passed to SetUsageWidth or FlagUsagesWrapped to wrap
+// usage to the width of the terminal Output() is connected to, as set in
+// the COLUMNS environment variable or, if it is unset, as reported by the
+// terminal. If Output() is not a terminal, e.g. a pipe or a file, usage is
+// not wrapped.
+const TerminalWidth = -1
+
+// SetUsageWidth sets the width PrintDefaults, and so the default usage
+// function, wraps flag usages to. Pass 0, the default, for no wrapping, or
+// TerminalWidth to detect the width of the terminal.
+func (f *FlagSet) SetUsageWidth(cols int) {
+	f.usageWidth = cols
+}
+
+// terminalWidth returns the number of columns of the terminal w is
+// connected to, taken from the COLUMNS environment variable if it is set.
+// It returns 0 if w is not a terminal, e.g. if it is a pipe.
+func terminalWidth(w io.Writer) int {
+	file, ok := w.(*os.File)
+	if !ok || !isTerminal(file.Fd()) {
+		return 0
+	}
+	if cols := columnsFromEnv(); cols > 0 {
+		return cols
+	}
+	return terminalColumns(file.Fd())
+}
+
+// columnsFromEnv returns the width set in the COLUMNS environment variable,
+// or 0 if it is unset or invalid.
+func columnsFromEnv() int {
+	cols, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil || cols < 0 {
+		return 0
+	}
+	return cols
+}
+
+// SetUsageWidth sets the width PrintDefaults wraps command-line flag usages to.
+func SetUsageWidth(cols int) {
+	CommandLine.SetUsageWidth(cols)
+}
@@ -0,0 +1,14 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!windows
+
+package pflag
+
+// isTerminal reports false, as terminals can't be detected on this platform.
+func isTerminal(_ uintptr) bool {
+	return false
+}
+
+// terminalColumns returns 0, as the terminal width is unknown on this platform.
+func terminalColumns(_ uintptr) int {
+	return 0
+}
@@ -0,0 +1,90 @@
+package pflag
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTerminalWidthNotATerminal(t *testing.T) {
+	if w := terminalWidth(&bytes.Buffer{}); w != 0 {
+		t.Errorf("expected width 0 for a buffer, got %d", w)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if width := terminalWidth(w); width != 0 {
+		t.Errorf("expected width 0 for a pipe, got %d", width)
+	}
+
+	defer os.Setenv("COLUMNS", os.Getenv("COLUMNS"))
+	_ = os.Setenv("COLUMNS", "40")
+	if width := terminalWidth(w); width != 0 {
+		t.Errorf("expected COLUMNS to be ignored for a pipe, got %d", width)
+	}
+}
+
+func TestColumnsFromEnv(t *testing.T) {
+	defer os.Setenv("COLUMNS", os.Getenv("COLUMNS"))
+
+	tests := map[string]int{
+		"":    0,
+		"100": 100,
+		"-1":  0,
+		"abc": 0,
+	}
+	for value, expected := range tests {
+		_ = os.Setenv("COLUMNS", value)
+		if cols := columnsFromEnv(); cols != expected {
+			t.Errorf("COLUMNS=%q: expected %d, got %d", value, expected, cols)
+		}
+	}
+}
+
+func TestPrintDefaultsUsageWidth(t *testing.T) {
+	defer os.Setenv("COLUMNS", os.Getenv("COLUMNS"))
+	_ = os.Setenv("COLUMNS", "60")
+
+	tests := []struct {
+		width   int
+		wrapped bool
+	}{
+		{0, false},
+		{TerminalWidth, false},
+		{60, true},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		f := NewFlagSet("test", ContinueOnError)
+		f.SetOutput(&buf)
+		f.String("name", "", strings.Repeat("word ", 20))
+		if test.width != 0 {
+			f.SetUsageWidth(test.width)
+		}
+
+		f.PrintDefaults()
+		if wrapped := strings.Count(buf.String(), "\n") > 1; wrapped != test.wrapped {
+			t.Errorf("width %d: expected wrapped to be %v, got %q", test.width, test.wrapped, buf.String())
+		}
+	}
+}
+
+func TestWrapNMultiByte(t *testing.T) {
+	s := strings.Repeat("héllo wörld ", 10)
+	l, r := wrapN(30, 5, s)
+	if !utf8.ValidString(l) || !utf8.ValidString(r) {
+		t.Fatalf("wrapN split a rune: %q / %q", l, r)
+	}
+	if n := utf8.RuneCountInString(l); n > 30 {
+		t.Errorf("expected at most 30 runes on the first line, got %d: %q", n, l)
+	}
+	if l+" "+r != s {
+		t.Errorf("wrapN lost text: %q / %q", l, r)
+	}
+}
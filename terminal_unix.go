@@ -0,0 +1,37 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package pflag
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+type winsize struct {
+	Row    uint16
+	Col    uint16
+	Xpixel uint16
+	Ypixel uint16
+}
+
+func getWinsize(fd uintptr) (*winsize, bool) {
+	ws := &winsize{}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(ws)))
+	return ws, errno == 0
+}
+
+// isTerminal returns true if the file descriptor is a terminal.
+func isTerminal(fd uintptr) bool {
+	_, ok := getWinsize(fd)
+	return ok
+}
+
+// terminalColumns returns the width of the terminal, or 0 if unknown.
+func terminalColumns(fd uintptr) int {
+	ws, ok := getWinsize(fd)
+	if !ok {
+		return 0
+	}
+	return int(ws.Col)
+}
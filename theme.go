@@ -0,0 +1,100 @@
+package pflag
+
+import (
+	"io"
+	"os"
+)
+
+// Theme defines the styles applied to usage output. Each field holds the
+// parameters of an ANSI SGR escape sequence, e.g. "1" for bold or "33" for
+// yellow; an empty field leaves that part unstyled.
+type Theme struct {
+	FlagName    string // flag names and shorthands
+	Varname     string // names of flag values
+	DefValue    string // default values
+	Deprecated  string // deprecation notices
+	GroupHeader string // usage group headers
+}
+
+// DefaultTheme is a theme which highlights flag names, dims default values
+// and colors deprecation notices.
+var DefaultTheme = Theme{
+	FlagName:    "1",
+	Varname:     "3",
+	DefValue:    "2",
+	Deprecated:  "33",
+	GroupHeader: "1;4",
+}
+
+// ColorMode decides when usage output is styled with the FlagSet's theme.
+type ColorMode int
+
+const (
+	// ColorAuto styles usage output only if Output() is a terminal and the
+	// NO_COLOR environment variable is not set
+	ColorAuto ColorMode = iota
+	// ColorAlways always styles usage output
+	ColorAlways
+	// ColorNever never styles usage output
+	ColorNever
+)
+
+// plainTheme applies no styles.
+var plainTheme = &Theme{}
+
+// orPlain returns the theme, or a theme which applies no styles if it is nil.
+func (t *Theme) orPlain() *Theme {
+	if t == nil {
+		return plainTheme
+	}
+	return t
+}
+
+// style wraps s in the escape sequences for the SGR parameters sgr. It
+// returns s unchanged if sgr is empty.
+func (t *Theme) style(sgr, s string) string {
+	if sgr == "" || s == "" {
+		return s
+	}
+	return "\x1b[" + sgr + "m" + s + "\x1b[0m"
+}
+
+// SetTheme sets the theme used to style usage output. Styling is disabled
+// if theme is nil, which is the default.
+func (f *FlagSet) SetTheme(theme *Theme) {
+	f.theme = theme
+}
+
+// SetColorMode sets when usage output is styled with the theme set with
+// SetTheme. The default is ColorAuto.
+func (f *FlagSet) SetColorMode(mode ColorMode) {
+	f.colorMode = mode
+}
+
+// activeTheme returns the theme to style usage output with, or nil if
+// usage output should not be styled.
+func (f *FlagSet) activeTheme() *Theme {
+	if f.theme == nil {
+		return nil
+	}
+	switch f.colorMode {
+	case ColorAlways:
+		return f.theme
+	case ColorNever:
+		return nil
+	}
+	if !colorSupported(f.Output()) {
+		return nil
+	}
+	return f.theme
+}
+
+// colorSupported returns true if w is a terminal and the user did not opt
+// out of colors with the NO_COLOR environment variable.
+func colorSupported(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	file, ok := w.(*os.File)
+	return ok && isTerminal(file.Fd())
+}
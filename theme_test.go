@@ -0,0 +1,66 @@
+package pflag
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestThemedUsage(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.StringP("name", "n", "world", "who to greet")
+	f.String("old", "", "old flag")
+	_ = f.MarkDeprecated("old", "use --name")
+	f.Lookup("old").Hidden = false
+	f.SetTheme(&DefaultTheme)
+	f.SetColorMode(ColorAlways)
+
+	expected := "  \x1b[1m-n\x1b[0m, \x1b[1m--name\x1b[0m \x1b[3mstring\x1b[0m   who to greet \x1b[2m(default \"world\")\x1b[0m\n" +
+		"      \x1b[1m--old\x1b[0m \x1b[3mstring\x1b[0m    old flag \x1b[33m(DEPRECATED: use --name)\x1b[0m\n"
+	if res := f.FlagUsages(); res != expected {
+		t.Errorf("Expected \n%q \nActual \n%q", expected, res)
+	}
+
+	f.SetColorMode(ColorNever)
+	if res := f.FlagUsages(); strings.Contains(res, "\x1b") {
+		t.Errorf("expected no styling with ColorNever, got %q", res)
+	}
+}
+
+func TestThemedGroupHeader(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.String("host", "", "host")
+	_ = f.SetFlagGroup("host", "Networking")
+	f.SetTheme(&Theme{GroupHeader: "1"})
+	f.SetColorMode(ColorAlways)
+
+	if res := f.FlagUsages(); !strings.HasPrefix(res, "\x1b[1mNetworking\x1b[0m:\n") {
+		t.Errorf("expected a styled group header, got %q", res)
+	}
+}
+
+func TestThemeDisabledAutomatically(t *testing.T) {
+	defer os.Setenv("NO_COLOR", os.Getenv("NO_COLOR"))
+	_ = os.Setenv("NO_COLOR", "")
+
+	var buf bytes.Buffer
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetOutput(&buf)
+	f.String("name", "", "name")
+	f.SetTheme(&DefaultTheme)
+	if f.activeTheme() != nil {
+		t.Error("expected no theme when output is not a terminal")
+	}
+
+	_ = os.Setenv("NO_COLOR", "1")
+	f.SetOutput(os.Stderr)
+	if f.activeTheme() != nil {
+		t.Error("expected no theme when NO_COLOR is set")
+	}
+
+	f.SetColorMode(ColorAlways)
+	if f.activeTheme() == nil {
+		t.Error("expected ColorAlways to override NO_COLOR")
+	}
+}
@@ -0,0 +1,128 @@
+package pflag
+
+// TokenKind is the kind of a command-line token.
+type TokenKind int
+
+const (
+	// LongFlagToken is a known flag given by name, e.g. "--flag" or "--flag=x".
+	LongFlagToken TokenKind = iota
+	// ShortFlagToken is a known flag given by shorthand. A cluster such as
+	// "-abc" yields one token per shorthand, all with the same Index.
+	ShortFlagToken
+	// UnknownFlagToken is a flag which is not defined in the FlagSet. It is
+	// only returned if the ParseErrorsAllowlist allows unknown flags.
+	UnknownFlagToken
+	// TerminatorToken is the "--" which terminates the flags.
+	TerminatorToken
+	// PositionalToken is a non-flag argument.
+	PositionalToken
+)
+
+// String returns the name of the token kind.
+func (k TokenKind) String() string {
+	switch k {
+	case LongFlagToken:
+		return "long flag"
+	case ShortFlagToken:
+		return "short flag"
+	case UnknownFlagToken:
+		return "unknown flag"
+	case TerminatorToken:
+		return "terminator"
+	case PositionalToken:
+		return "positional"
+	}
+	return "invalid"
+}
+
+// ValueOrigin tells where the value of a flag token came from.
+type ValueOrigin int
+
+const (
+	// NoValue means the token has no value, e.g. an unknown boolean-like flag
+	// or the terminator.
+	NoValue ValueOrigin = iota
+	// InlineValue means the value was part of the flag argument, as in
+	// "--flag=x", "-f=x" or "-fx".
+	InlineValue
+	// NextArgValue means the value was the argument following the flag.
+	NextArgValue
+	// NoOptDefValue means the flag was given without a value and its
+	// NoOptDefVal was used.
+	NoOptDefValue
+)
+
+// String returns the name of the value origin.
+func (o ValueOrigin) String() string {
+	switch o {
+	case NoValue:
+		return "none"
+	case InlineValue:
+		return "inline"
+	case NextArgValue:
+		return "next argument"
+	case NoOptDefValue:
+		return "NoOptDefVal"
+	}
+	return "invalid"
+}
+
+// A Token is one element of the command line as understood by the parser.
+type Token struct {
+	Kind        TokenKind
+	Index       int         // index of the argument the token was read from
+	Arg         string      // the argument the token was read from, e.g. "-abc"
+	Name        string      // flag name or shorthand as given; empty for non-flag tokens
+	Flag        *Flag       // the flag, nil for unknown flags and non-flag tokens
+	Value       string      // value of the flag, or the positional argument
+	ValueOrigin ValueOrigin // where Value came from
+	ValueIndex  int         // index of the argument Value was read from
+
+	passed string // the non-flag argument an unknown flag is passed as, if any
+}
+
+// Tokenize splits the argument list, which should not include the command
+// name, into tokens the way Parse reads it, without setting any flag. The
+// FlagSet is left unchanged and no usage or warnings are printed. Errors are
+// always returned, whatever the error handling property of the FlagSet,
+// together with the tokens read before the error. ErrHelp is returned if
+// -help or -h was given but not defined.
+func (f *FlagSet) Tokenize(arguments []string) ([]Token, error) {
+	tokens, err := f.tokenize(arguments)
+	if err != nil && f.allowsError(err) {
+		err = nil
+	}
+	return tokens, f.translateError(err)
+}
+
+// A tokenizer splits arguments into tokens. It only reads the flags and the
+// settings of its FlagSet, so that the same arguments always give the same
+// tokens.
+type tokenizer struct {
+	f      *FlagSet
+	tokens []Token
+	index  int    // index of the argument being read
+	arg    string // the argument being read
+}
+
+// tokenize splits the arguments into tokens, stopping at the first error.
+// The tokens read before the error are returned with it.
+func (f *FlagSet) tokenize(arguments []string) ([]Token, error) {
+	tk := &tokenizer{f: f}
+	err := tk.parseArgs(arguments)
+	return tk.tokens, err
+}
+
+// emit adds a token.
+func (tk *tokenizer) emit(t Token) {
+	tk.tokens = append(tk.tokens, t)
+}
+
+// emitPositionals adds the arguments as positional tokens, the first one
+// being at index tk.index.
+func (tk *tokenizer) emitPositionals(args ...string) {
+	for i, arg := range args {
+		index := tk.index + i
+		tk.emit(Token{Kind: PositionalToken, Index: index, Arg: arg, Value: arg, ValueIndex: index})
+	}
+}
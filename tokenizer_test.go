@@ -0,0 +1,150 @@
+package pflag
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func setUpTokenizerFlagSet() *FlagSet {
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	f.BoolP("all", "a", false, "all")
+	f.BoolP("brief", "b", false, "brief")
+	f.IntP("count", "n", 1, "count")
+	f.StringP("file", "f", "", "file")
+	f.String("color", "auto", "color")
+	f.Lookup("color").NoOptDefVal = "always"
+	return f
+}
+
+// tokenSummary is the part of a token compared by the tests.
+type tokenSummary struct {
+	Kind        TokenKind
+	Index       int
+	Name        string
+	Value       string
+	ValueOrigin ValueOrigin
+	ValueIndex  int
+}
+
+func summarizeTokens(tokens []Token) []tokenSummary {
+	s := make([]tokenSummary, len(tokens))
+	for i, t := range tokens {
+		s[i] = tokenSummary{t.Kind, t.Index, t.Name, t.Value, t.ValueOrigin, t.ValueIndex}
+	}
+	return s
+}
+
+func TestTokenize(t *testing.T) {
+	f := setUpTokenizerFlagSet()
+	args := []string{"--count", "3", "-abn1234", "x", "--file=a.txt", "-f=b", "-f", "c", "--color", "--", "-a", "y"}
+	tokens, err := f.Tokenize(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []tokenSummary{
+		{LongFlagToken, 0, "count", "3", NextArgValue, 1},
+		{ShortFlagToken, 2, "a", "true", NoOptDefValue, 2},
+		{ShortFlagToken, 2, "b", "true", NoOptDefValue, 2},
+		{ShortFlagToken, 2, "n", "1234", InlineValue, 2},
+		{PositionalToken, 3, "", "x", NoValue, 3},
+		{LongFlagToken, 4, "file", "a.txt", InlineValue, 4},
+		{ShortFlagToken, 5, "f", "b", InlineValue, 5},
+		{ShortFlagToken, 6, "f", "c", NextArgValue, 7},
+		{LongFlagToken, 8, "color", "always", NoOptDefValue, 8},
+		{TerminatorToken, 9, "", "", NoValue, 9},
+		{PositionalToken, 10, "", "-a", NoValue, 10},
+		{PositionalToken, 11, "", "y", NoValue, 11},
+	}
+	if got := summarizeTokens(tokens); !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected tokens:\n got %+v\nwant %+v", got, expected)
+	}
+	if tokens[1].Arg != "-abn1234" || tokens[0].Flag != f.Lookup("count") {
+		t.Errorf("unexpected token details: %+v", tokens[0:2])
+	}
+	if f.Changed("count") || f.Parsed() || len(f.Args()) != 0 {
+		t.Error("expected Tokenize to leave the flag set unchanged")
+	}
+}
+
+func TestTokenizeNotInterspersed(t *testing.T) {
+	f := setUpTokenizerFlagSet()
+	f.SetInterspersed(false)
+	tokens, err := f.Tokenize([]string{"-a", "cmd", "-b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []tokenSummary{
+		{ShortFlagToken, 0, "a", "true", NoOptDefValue, 0},
+		{PositionalToken, 1, "", "cmd", NoValue, 1},
+		{PositionalToken, 2, "", "-b", NoValue, 2},
+	}
+	if got := summarizeTokens(tokens); !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected tokens:\n got %+v\nwant %+v", got, expected)
+	}
+}
+
+func TestTokenizeUnknownFlags(t *testing.T) {
+	f := setUpTokenizerFlagSet()
+	f.ParseErrorsAllowlist.UnknownFlagsHandling = IgnoreUnknownFlag
+	tokens, err := f.Tokenize([]string{"--unknown", "value", "--other=x", "-az"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []tokenSummary{
+		{UnknownFlagToken, 0, "unknown", "value", NextArgValue, 1},
+		{UnknownFlagToken, 2, "other", "x", InlineValue, 2},
+		{ShortFlagToken, 3, "a", "true", NoOptDefValue, 3},
+		{UnknownFlagToken, 3, "z", "", NoValue, 3},
+	}
+	if got := summarizeTokens(tokens); !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected tokens:\n got %+v\nwant %+v", got, expected)
+	}
+}
+
+func TestTokenizeError(t *testing.T) {
+	f := NewFlagSet("test", ExitOnError)
+	out := &bytes.Buffer{}
+	f.SetOutput(out)
+	f.Bool("all", false, "all")
+	tokens, err := f.Tokenize([]string{"--all", "--nope"})
+	if err == nil || err.Error() != "unknown flag: --nope" {
+		t.Errorf("expected unknown flag error, got %v", err)
+	}
+	if len(tokens) != 1 || tokens[0].Name != "all" {
+		t.Errorf("expected the tokens before the error, got %+v", tokens)
+	}
+	if _, err := f.Tokenize([]string{"--help"}); err != ErrHelp { //nolint:errorlint
+		t.Errorf("expected ErrHelp, got %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected no output, got %q", out.String())
+	}
+}
+
+func TestTokenizeDuringParse(t *testing.T) {
+	f := setUpTokenizerFlagSet()
+	var tokens []Token
+	err := f.ParseAll([]string{"-ab", "x", "--count=2", "y"}, func(flag *Flag, value string) error {
+		if flag.Name == "all" {
+			var err error
+			if tokens, err = f.Tokenize([]string{"--", "z"}); err != nil {
+				return err
+			}
+		}
+		return f.Set(flag.Name, value)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 2 || tokens[1].Value != "z" {
+		t.Errorf("unexpected tokens: %+v", tokens)
+	}
+	if !reflect.DeepEqual(f.Args(), []string{"x", "y"}) || f.ArgsLenAtDash() != -1 {
+		t.Errorf("expected Tokenize not to affect the parse, got %q and %d", f.Args(), f.ArgsLenAtDash())
+	}
+	if brief := f.Occurrences("brief"); len(brief) != 1 || brief[0].Arg != "-ab" || brief[0].Index != 0 {
+		t.Errorf("unexpected occurrences of brief: %+v", brief)
+	}
+}
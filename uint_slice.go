@@ -42,6 +42,11 @@ func (s *uintSliceValue) Type() string {
 	return "uintSlice"
 }
 
+// clearChanged makes the next Set replace the value instead of adding to it.
+func (s *uintSliceValue) clearChanged() {
+	s.changed = false
+}
+
 func (s *uintSliceValue) String() string {
 	out := make([]string, len(*s.value))
 	for i, d := range *s.value {
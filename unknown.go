@@ -0,0 +1,31 @@
+package pflag
+
+// An UnknownFlag is a flag given on the command line which is not defined in
+// the FlagSet, collected when the ParseErrorsAllowlist allows unknown flags.
+type UnknownFlag struct {
+	Name        string      // name or shorthand as given, without dashes
+	Shorthand   bool        // the flag was given in its short form, e.g. -x
+	Arg         string      // the argument the flag was read from, e.g. "--name=value" or "-abx"
+	Value       string      // value given with the flag, if any
+	ValueOrigin ValueOrigin // where Value came from: NoValue, InlineValue or, with IgnoreUnknownFlag, NextArgValue
+	Index       int         // index of the argument in the arguments passed to Parse
+}
+
+// UnknownFlags returns the unknown flags found by the last call to Parse or
+// ParseAll, in command-line order. With ErrorOnUnknownFlag parsing stops at
+// the first unknown flag, so none are returned.
+func (f *FlagSet) UnknownFlags() []UnknownFlag {
+	return f.unknownFlags
+}
+
+// recordUnknownFlag records the unknown flag read as token t.
+func (f *FlagSet) recordUnknownFlag(t Token) {
+	f.unknownFlags = append(f.unknownFlags, UnknownFlag{
+		Name:        t.Name,
+		Shorthand:   t.Arg[1] != '-',
+		Arg:         t.Arg,
+		Value:       t.Value,
+		ValueOrigin: t.ValueOrigin,
+		Index:       t.Index,
+	})
+}
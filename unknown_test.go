@@ -0,0 +1,59 @@
+package pflag
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestUnknownFlags(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	f.BoolP("all", "a", false, "all")
+	f.ParseErrorsAllowlist.UnknownFlagsHandling = IgnoreUnknownFlag
+
+	args := []string{"--unknown", "value", "-ax", "--other=x", "arg", "-y=1", "--last"}
+	if err := f.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	expected := []UnknownFlag{
+		{Name: "unknown", Arg: "--unknown", Value: "value", ValueOrigin: NextArgValue, Index: 0},
+		{Name: "x", Shorthand: true, Arg: "-ax", Index: 2},
+		{Name: "other", Arg: "--other=x", Value: "x", ValueOrigin: InlineValue, Index: 3},
+		{Name: "y", Shorthand: true, Arg: "-y=1", Value: "1", ValueOrigin: InlineValue, Index: 5},
+		{Name: "last", Arg: "--last", Index: 6},
+	}
+	if got := f.UnknownFlags(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected unknown flags:\n got %+v\nwant %+v", got, expected)
+	}
+	if !reflect.DeepEqual(f.Args(), []string{"arg"}) {
+		t.Errorf("unexpected args: %q", f.Args())
+	}
+
+	if err := f.Parse([]string{"-a"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.UnknownFlags(); got != nil {
+		t.Errorf("expected unknown flags to be reset by Parse, got %+v", got)
+	}
+}
+
+func TestUnknownFlagsPassedToArgs(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.SetOutput(&bytes.Buffer{})
+	f.ParseErrorsAllowlist.UnknownFlagsHandling = PassUnknownFlagToArgs
+
+	if err := f.Parse([]string{"--unknown", "value", "-xy"}); err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, u := range f.UnknownFlags() {
+		names = append(names, u.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"unknown", "x", "y"}) {
+		t.Errorf("unexpected unknown flags: %q", names)
+	}
+	if !reflect.DeepEqual(f.Args(), []string{"--unknown", "value", "-xy"}) {
+		t.Errorf("unexpected args: %q", f.Args())
+	}
+}
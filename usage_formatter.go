@@ -0,0 +1,176 @@
+package pflag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FlagUsage is the structured usage information of a single flag, as passed
+// to a UsageFormatter.
+type FlagUsage struct {
+	Flag *Flag // the flag being described
+
+	Name      string // long name of the flag
+	Shorthand string // shorthand shown in usage; empty if none or deprecated
+	Varname   string // name of the flag's value, e.g. "string"; empty for booleans
+	IsBool    bool   // the flag is a boolean which accepts an optional true|false value
+
+	// OptionalValue is the value used when the flag is given without an
+	// argument, formatted for display. It is empty if the flag requires a
+	// value or if the value is the obvious one (true, or +1 for counts).
+	OptionalValue string
+
+	// ValueOptional is true if the value of the flag may be given as the
+	// next argument or omitted, per its OptionalValuePolicy.
+	ValueOptional bool
+
+	Usage      string // usage text, with back quotes removed
+	DefValue   string // default value formatted for display; empty if it is the zero value
+	Deprecated string // deprecation message; empty if the flag is not deprecated
+
+	Translator Translator // translator of the FlagSet, for the text added by formatters; may be nil
+	Theme      *Theme     // theme to style the usage with; nil if usage is not styled
+}
+
+// UsageWidths holds the column widths computed for a block of flags.
+type UsageWidths struct {
+	Names int // display width of the widest names column, as returned by UsageFormatter.FlagNames
+	Cols  int // total width to wrap to, 0 for no wrapping
+}
+
+// UsageFormatter renders the usage of flags. FlagNames is called for every
+// flag of a block first, so that the column widths can be computed, then
+// FormatFlag is called with those widths to render each flag.
+type UsageFormatter interface {
+	// FlagNames returns the names column for the flag, e.g. "  -v, --verbose".
+	FlagNames(u FlagUsage) string
+	// FormatFlag returns the complete usage entry for the flag, including the
+	// trailing newline. names is the value returned by FlagNames.
+	FormatFlag(u FlagUsage, names string, widths UsageWidths) string
+}
+
+// DefaultUsageFormatter is the UsageFormatter used by FlagUsagesWrapped unless
+// another one is set with SetUsageFormatter. It can be embedded by formatters
+// which only need to change part of the layout.
+type DefaultUsageFormatter struct{}
+
+// FlagNames implements UsageFormatter.
+func (DefaultUsageFormatter) FlagNames(u FlagUsage) string {
+	t := u.Theme.orPlain()
+	names := ""
+	if u.Shorthand != "" {
+		names = fmt.Sprintf("  %s, %s", t.style(t.FlagName, "-"+u.Shorthand), t.style(t.FlagName, "--"+u.Name))
+	} else {
+		names = fmt.Sprintf("      %s", t.style(t.FlagName, "--"+u.Name))
+	}
+
+	if u.ValueOptional {
+		return names + " " + t.style(t.Varname, optionalVarname(u))
+	}
+	if u.IsBool {
+		names += "[=true|false]"
+	} else if u.Varname != "" {
+		names += " " + t.style(t.Varname, u.Varname)
+	}
+	if u.OptionalValue != "" {
+		names += fmt.Sprintf("[=%s]", u.OptionalValue)
+	}
+	return names
+}
+
+// optionalVarname returns how the value of a flag which may take the next
+// argument as its optional value is shown, e.g. "[string]" or
+// "[string=\"auto\"]".
+func optionalVarname(u FlagUsage) string {
+	if u.IsBool {
+		return "[true|false]"
+	}
+	name := u.Varname
+	if name == "" {
+		name = "value"
+	}
+	if u.OptionalValue != "" {
+		name += "=" + u.OptionalValue
+	}
+	return "[" + name + "]"
+}
+
+// FormatFlag implements UsageFormatter.
+func (DefaultUsageFormatter) FormatFlag(u FlagUsage, names string, widths UsageWidths) string {
+	t := u.Theme.orPlain()
+	usage := u.Usage
+	if u.DefValue != "" {
+		usage += " " + t.style(t.DefValue, u.Translator.translate(MsgUsageDefault, map[string]string{"value": u.DefValue}))
+	}
+	if u.Deprecated != "" {
+		usage += " " + t.style(t.Deprecated, u.Translator.translate(MsgUsageDeprecated, map[string]string{"message": u.Deprecated}))
+	}
+
+	// The usage column starts three spaces after the widest names.
+	indent := widths.Names + 3
+	spacing := strings.Repeat(" ", indent-displayWidth(names))
+	return names + spacing + WrapUsage(indent, widths.Cols, usage) + "\n"
+}
+
+// WrapUsage wraps the string s to a maximum width cols with leading indent
+// indent, as done for the usage column by DefaultUsageFormatter. The first
+// line is not indented. Pass cols == 0 to do no wrapping.
+func WrapUsage(indent, cols int, s string) string {
+	return wrap(indent, cols, s)
+}
+
+// newFlagUsage returns the structured usage information of the flag.
+func newFlagUsage(flag *Flag) FlagUsage {
+	u := FlagUsage{
+		Flag:       flag,
+		Name:       flag.Name,
+		Deprecated: flag.Deprecated,
+	}
+	if flag.ShorthandDeprecated == "" {
+		u.Shorthand = flag.Shorthand
+	}
+
+	u.Varname, u.Usage = UnquoteUsage(flag)
+	u.IsBool = isNoOptBoolValue(flag.Value) && flag.Value.Type() == "bool"
+
+	if flag.NoOptDefVal != "" {
+		u.ValueOptional = flag.OptionalValuePolicy != OptionalValueInline
+		switch flag.Value.Type() {
+		case "string":
+			u.OptionalValue = fmt.Sprintf("\"%s\"", flag.NoOptDefVal)
+		case "bool", "boolfunc":
+			if flag.NoOptDefVal != "true" {
+				u.OptionalValue = flag.NoOptDefVal
+			}
+		case "count":
+			if flag.NoOptDefVal != "+1" {
+				u.OptionalValue = flag.NoOptDefVal
+			}
+		default:
+			u.OptionalValue = flag.NoOptDefVal
+		}
+	}
+
+	if !flag.defaultIsZeroValue() {
+		if flag.Value.Type() == "string" {
+			u.DefValue = fmt.Sprintf("%q", flag.DefValue)
+		} else {
+			u.DefValue = flag.DefValue
+		}
+	}
+	return u
+}
+
+// SetUsageFormatter sets the formatter used to render flag usages. If
+// formatter is nil, DefaultUsageFormatter is used.
+func (f *FlagSet) SetUsageFormatter(formatter UsageFormatter) {
+	f.usageFormatter = formatter
+}
+
+// GetUsageFormatter returns the formatter used to render flag usages.
+func (f *FlagSet) GetUsageFormatter() UsageFormatter {
+	if f.usageFormatter != nil {
+		return f.usageFormatter
+	}
+	return DefaultUsageFormatter{}
+}
@@ -0,0 +1,67 @@
+package pflag
+
+import (
+	"strings"
+	"testing"
+)
+
+// twoLineFormatter prints the flag names on one line and the usage on the next.
+type twoLineFormatter struct{}
+
+func (twoLineFormatter) FlagNames(u FlagUsage) string {
+	names := "--" + u.Name
+	if u.Shorthand != "" {
+		names = "-" + u.Shorthand + ", " + names
+	}
+	if u.Varname != "" {
+		names += "=" + strings.ToUpper(u.Varname)
+	}
+	return names
+}
+
+func (twoLineFormatter) FormatFlag(u FlagUsage, names string, widths UsageWidths) string {
+	return names + "\n" + strings.Repeat(" ", 8) + WrapUsage(8, widths.Cols, u.Usage) + "\n"
+}
+
+func TestUsageFormatter(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.StringP("name", "n", "", "the `who` to greet")
+	f.Bool("loud", false, "greet loudly")
+	f.SetUsageFormatter(twoLineFormatter{})
+
+	expected := `--loud
+        greet loudly
+-n, --name=WHO
+        the who to greet
+`
+	if res := f.FlagUsages(); res != expected {
+		t.Errorf("Expected \n%s \nActual \n%s", expected, res)
+	}
+
+	f.SetUsageFormatter(nil)
+	if _, ok := f.GetUsageFormatter().(DefaultUsageFormatter); !ok {
+		t.Errorf("expected DefaultUsageFormatter after resetting, got %T", f.GetUsageFormatter())
+	}
+}
+
+func TestNewFlagUsage(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.StringP("name", "n", "world", "the `who` to greet")
+	f.Lookup("name").NoOptDefVal = "everyone"
+	f.BoolP("loud", "l", false, "greet loudly")
+	_ = f.MarkShorthandDeprecated("loud", "use --loud")
+	_ = f.MarkDeprecated("loud", "be quiet")
+
+	u := newFlagUsage(f.Lookup("name"))
+	if u.Name != "name" || u.Shorthand != "n" || u.Varname != "who" || u.Usage != "the who to greet" {
+		t.Errorf("unexpected names in %+v", u)
+	}
+	if u.DefValue != `"world"` || u.OptionalValue != `"everyone"` || u.IsBool {
+		t.Errorf("unexpected values in %+v", u)
+	}
+
+	u = newFlagUsage(f.Lookup("loud"))
+	if u.Shorthand != "" || !u.IsBool || u.Varname != "" || u.OptionalValue != "" || u.DefValue != "" || u.Deprecated != "be quiet" {
+		t.Errorf("unexpected usage for loud: %+v", u)
+	}
+}
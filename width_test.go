@@ -0,0 +1,73 @@
+package pflag
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"héllo", 5},
+		{"he\u0301llo", 5}, // combining acute accent
+		{"日本語", 6},
+		{"ｆｕｌｌ", 8},
+		{"한국어", 6},
+		{"🚀 go", 5},
+		{"a\u200bb", 2}, // zero width space
+	}
+	for _, test := range tests {
+		if w := displayWidth(test.s); w != test.width {
+			t.Errorf("displayWidth(%q): expected %d, got %d", test.s, test.width, w)
+		}
+	}
+}
+
+func TestWidthOffset(t *testing.T) {
+	s := "日本語abc"
+	if n := widthOffset(s, 3); s[:n] != "日" {
+		t.Errorf("expected only one double-width rune to fit in 3 columns, got %q", s[:n])
+	}
+	if n := widthOffset(s, 4); s[:n] != "日本" {
+		t.Errorf("expected two double-width runes to fit in 4 columns, got %q", s[:n])
+	}
+	if n := widthOffset(s, 100); n != len(s) {
+		t.Errorf("expected the whole string to fit, got offset %d", n)
+	}
+}
+
+func TestFlagUsagesDoubleWidthAlignment(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.String("名前", "", "あなたの名前")
+	f.String("name", "", "your name")
+
+	expected := `      --name string   your name
+      --名前 string   あなたの名前
+`
+	if res := f.FlagUsages(); res != expected {
+		t.Errorf("Expected \n%s \nActual \n%s", expected, res)
+	}
+}
+
+func TestFlagUsagesWrappedDoubleWidth(t *testing.T) {
+	f := NewFlagSet("test", ContinueOnError)
+	f.String("name", "", strings.Repeat("日本語のテキスト ", 12))
+
+	res := f.FlagUsagesWrapped(60)
+	for _, line := range strings.Split(strings.TrimSuffix(res, "\n"), "\n") {
+		if !utf8.ValidString(line) {
+			t.Fatalf("line contains a split rune: %q", line)
+		}
+		if w := displayWidth(line); w > 60 {
+			t.Errorf("line is %d columns wide, expected at most 60: %q", w, line)
+		}
+	}
+	if strings.Count(res, "\n") < 3 {
+		t.Errorf("expected the usage to be wrapped, got %q", res)
+	}
+}